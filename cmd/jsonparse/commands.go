@@ -0,0 +1,197 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Ronit-Raj/json-parser/parser"
+	"github.com/Ronit-Raj/json-parser/pointer"
+)
+
+// runValidate checks the syntax of each input and prints nothing for the
+// valid ones.
+func runValidate(e *env, fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	e.each(fs.Args(), func(name, text string) error {
+		// A RawMessage checks the syntax without building the document.
+		var raw parser.RawMessage
+		return parser.Decode(text, &raw)
+	})
+	return nil
+}
+
+// runFmt prints each input indented, or with all white space removed.
+func runFmt(e *env, fs *flag.FlagSet, args []string) error {
+	indent := fs.Int("indent", 2, "indent each level by `n` spaces")
+	tab := fs.Bool("tab", false, "indent with tabs instead of spaces")
+	minify := fs.Bool("minify", false, "remove all insignificant white space")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *indent < 0 {
+		return usageError(fs, "negative indent %d", *indent)
+	}
+	unit := strings.Repeat(" ", *indent)
+	if *tab {
+		unit = "\t"
+	}
+
+	e.each(fs.Args(), func(name, text string) error {
+		var out string
+		var err error
+		if *minify {
+			out, err = parser.Compact(text)
+		} else {
+			out, err = parser.Indent(text, "", unit)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(e.stdout, out)
+		return nil
+	})
+	return nil
+}
+
+// runGet prints the value a pointer refers to in each input, compacted. With
+// -r a string is printed without quotes or escapes.
+func runGet(e *env, fs *flag.FlagSet, args []string) error {
+	rawStrings := fs.Bool("r", false, "print strings without quotes")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return usageError(fs, "missing pointer")
+	}
+	ptr, err := pointer.Parse(fs.Arg(0))
+	if err != nil {
+		return usageError(fs, "%v", err)
+	}
+
+	files := fs.Args()[1:]
+	e.each(files, func(name, text string) error {
+		raw, err := lookup(ptr, text)
+		if err != nil {
+			return err
+		}
+		out, err := parser.Compact(string(raw))
+		if err != nil {
+			return err
+		}
+		if *rawStrings && strings.HasPrefix(out, `"`) {
+			if err := parser.Decode(out, &out); err != nil {
+				return err
+			}
+		}
+		e.print(name, len(files) > 1, out)
+		return nil
+	})
+	return nil
+}
+
+// runKeys lists the keys of an object in order, or the indexes of an array,
+// one per line.
+func runKeys(e *env, fs *flag.FlagSet, args []string) error {
+	return lookupEach(e, fs, args, func(name string, v any, prefixed bool) error {
+		switch v := v.(type) {
+		case *parser.OrderedObject:
+			for _, key := range v.Keys() {
+				e.print(name, prefixed, key)
+			}
+		case []any:
+			for i := range v {
+				e.print(name, prefixed, fmt.Sprint(i))
+			}
+		default:
+			return fmt.Errorf("%s has no keys", kind(v))
+		}
+		return nil
+	})
+}
+
+// runLen prints the number of members of an object, elements of an array
+// or characters of a string.
+func runLen(e *env, fs *flag.FlagSet, args []string) error {
+	return lookupEach(e, fs, args, func(name string, v any, prefixed bool) error {
+		var n int
+		switch v := v.(type) {
+		case *parser.OrderedObject:
+			n = v.Len()
+		case []any:
+			n = len(v)
+		case string:
+			n = utf8.RuneCountInString(v)
+		default:
+			return fmt.Errorf("%s has no length", kind(v))
+		}
+		e.print(name, prefixed, fmt.Sprint(n))
+		return nil
+	})
+}
+
+// lookup returns the source text of the value ptr names in text. GetRaw
+// stops reading at the end of that value, so the whole of text is checked
+// first: a malformed document is an error wherever the problem is.
+func lookup(ptr pointer.Pointer, text string) (parser.RawMessage, error) {
+	var raw parser.RawMessage
+	if err := parser.Decode(text, &raw); err != nil {
+		return nil, err
+	}
+	return ptr.GetRaw(text)
+}
+
+// lookupEach parses the -p flag of keys and len and calls f with the value
+// the pointer refers to in each input, decoded with its key order and
+// digits kept. prefixed is set if there are several inputs.
+func lookupEach(e *env, fs *flag.FlagSet, args []string, f func(name string, v any, prefixed bool) error) error {
+	ptrFlag := fs.String("p", "", "look at the value `pointer` refers to instead of the whole document")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	ptr, err := pointer.Parse(*ptrFlag)
+	if err != nil {
+		return usageError(fs, "%v", err)
+	}
+
+	e.each(fs.Args(), func(name, text string) error {
+		raw, err := lookup(ptr, text)
+		if err != nil {
+			return err
+		}
+		var v any
+		if err := parser.DecodeWithOptions(string(raw), &v, parser.UseOrderedObject(), parser.UseNumber()); err != nil {
+			return err
+		}
+		return f(name, v, fs.NArg() > 1)
+	})
+	return nil
+}
+
+// print writes one line of output, preceded by the name of the input if
+// prefixed is set.
+func (e *env) print(name string, prefixed bool, line string) {
+	if prefixed {
+		fmt.Fprintf(e.stdout, "%s: %s\n", name, line)
+		return
+	}
+	fmt.Fprintln(e.stdout, line)
+}
+
+// kind names the kind of JSON value v is for error messages.
+func kind(v any) string {
+	switch v.(type) {
+	case parser.Number:
+		return "a number"
+	case string:
+		return "a string"
+	case bool:
+		return "a boolean"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", v)
+}
@@ -0,0 +1,178 @@
+// Command jsonparse validates, formats and queries JSON documents from the
+// shell.
+//
+// Usage:
+//
+//	jsonparse validate [file ...]
+//	jsonparse fmt [-indent n | -tab | -minify] [file ...]
+//	jsonparse get [-r] pointer [file ...]
+//	jsonparse keys [-p pointer] [file ...]
+//	jsonparse len [-p pointer] [file ...]
+//
+// Every command reads the named files in turn, or standard input if there
+// are none or a file is named "-". Problems with the input are reported on
+// standard error as file:line:col: message, followed by the offending line.
+//
+// The exit status is 0 if every input was processed, 1 if any was invalid or
+// a lookup in it failed, and 2 if the command line itself was wrong.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Ronit-Raj/json-parser/scanner"
+)
+
+// Exit statuses.
+const (
+	exitOK     = 0
+	exitFailed = 1
+	exitUsage  = 2
+)
+
+const (
+	programName = "jsonparse"
+	stdinName   = "-"       // the file name that stands for standard input
+	stdinLabel  = "<stdin>" // how messages refer to standard input
+)
+
+// command is one of the subcommands of jsonparse.
+type command struct {
+	usage string // the arguments, for the usage message
+	help  string // what the command does, in one line
+	run   func(e *env, fs *flag.FlagSet, args []string) error
+}
+
+var commands = map[string]command{
+	"validate": {usage: "[file ...]", help: "check that each input is valid JSON", run: runValidate},
+	"fmt":      {usage: "[-indent n | -tab | -minify] [file ...]", help: "pretty-print or minify each input", run: runFmt},
+	"get":      {usage: "[-r] pointer [file ...]", help: "print the value a JSON Pointer refers to", run: runGet},
+	"keys":     {usage: "[-p pointer] [file ...]", help: "list the keys of an object or the indexes of an array", run: runKeys},
+	"len":      {usage: "[-p pointer] [file ...]", help: "print the length of an array, object or string", run: runLen},
+}
+
+// commandOrder lists the commands in the order usage describes them.
+var commandOrder = []string{"validate", "fmt", "get", "keys", "len"}
+
+// errUsage reports a wrong command line. The problem has been printed by
+// the time it is returned.
+var errUsage = errors.New("usage")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command line args and returns the exit status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "help" {
+		usage(stderr)
+		if len(args) == 0 {
+			return exitUsage
+		}
+		return exitOK
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "%s: unknown command %q\n", programName, args[0])
+		usage(stderr)
+		return exitUsage
+	}
+
+	fs := flag.NewFlagSet(programName+" "+args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: %s %s %s\n", programName, args[0], cmd.usage)
+		fs.PrintDefaults()
+	}
+	e := &env{stdin: stdin, stdout: stdout, stderr: stderr}
+	err := cmd.run(e, fs, args[1:])
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case err != nil:
+		return exitUsage
+	case e.failed:
+		return exitFailed
+	}
+	return exitOK
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintf(w, "usage: %s <command> [arguments]\n\ncommands:\n", programName)
+	for _, name := range commandOrder {
+		fmt.Fprintf(w, "  %-9s %s\n", name, commands[name].help)
+	}
+	fmt.Fprintf(w, "\nRun '%s <command> -h' for the arguments of a command.\n", programName)
+}
+
+// env is what a command runs in.
+type env struct {
+	stdin          io.Reader
+	stdout, stderr io.Writer
+	failed         bool // set once any input has been reported
+}
+
+// each calls f with the name and contents of each input file, or of
+// standard input if there are none. An error reading a file or returned by
+// f is reported, and the remaining files are still processed.
+func (e *env) each(files []string, f func(name, text string) error) {
+	if len(files) == 0 {
+		files = []string{stdinName}
+	}
+	for _, file := range files {
+		name, text, err := e.read(file)
+		if err == nil {
+			err = f(name, text)
+		}
+		if err != nil {
+			e.report(name, err)
+		}
+	}
+}
+
+// read returns the label errors use for file and its contents.
+func (e *env) read(file string) (string, string, error) {
+	if file == stdinName {
+		data, err := io.ReadAll(e.stdin)
+		return stdinLabel, string(data), err
+	}
+	data, err := os.ReadFile(file)
+	var pathErr *os.PathError
+	if errors.As(err, &pathErr) {
+		// report names the file already.
+		err = pathErr.Err
+	}
+	if err != nil {
+		return file, "", err
+	}
+	return file, string(data), nil
+}
+
+// report prints err, which input name caused, and marks the run failed. A
+// syntax error is located by line and column and shows the source line.
+func (e *env) report(name string, err error) {
+	e.failed = true
+	var syntaxErr scanner.SyntaxError
+	if errors.As(err, &syntaxErr) && syntaxErr.Line > 0 {
+		fmt.Fprintf(e.stderr, "%s:%d:%d: %s\n", name, syntaxErr.Line, syntaxErr.Column, syntaxErr.Msg)
+		if syntaxErr.Excerpt != "" {
+			for line := range strings.SplitSeq(syntaxErr.Excerpt, "\n") {
+				fmt.Fprintf(e.stderr, "\t%s\n", line)
+			}
+		}
+		return
+	}
+	fmt.Fprintf(e.stderr, "%s: %v\n", name, err)
+}
+
+// usageError prints a problem with the command line and returns errUsage.
+func usageError(fs *flag.FlagSet, format string, args ...any) error {
+	fmt.Fprintf(fs.Output(), "%s: %s\n", fs.Name(), fmt.Sprintf(format, args...))
+	fs.Usage()
+	return errUsage
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"config.json": `{"name": "api", "replicas": 3, "price": 1.50, "tags": ["a", "b"], "env": {"B": "1", "A": "2"}}`,
+		"other.json":  `{"name": "web", "tags": []}`,
+		"bad.json":    "{\n  \"a\": [1,\n    2,,]\n}",
+	}
+	for name, text := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	config, other, bad := filepath.Join(dir, "config.json"), filepath.Join(dir, "other.json"), filepath.Join(dir, "bad.json")
+	missing := filepath.Join(dir, "missing.json")
+
+	tests := []struct {
+		name   string
+		args   []string
+		stdin  string
+		status int
+		stdout string
+		stderr string // a part of the expected standard error
+	}{
+		{name: "validate", args: []string{"validate", config, other}, status: exitOK},
+		{name: "validate stdin", args: []string{"validate"}, stdin: `[1, 2]`, status: exitOK},
+		{name: "validate invalid", args: []string{"validate", config, bad}, status: exitFailed,
+			stderr: bad + ":3:7: unexpected \",\" looking for beginning of value\n\t    2,,]\n\t      ^\n"},
+		{name: "validate stdin invalid", args: []string{"validate", "-"}, stdin: `{"a" 1}`, status: exitFailed,
+			stderr: "<stdin>:1:6: "},
+		{name: "validate missing file", args: []string{"validate", missing}, status: exitFailed,
+			stderr: missing + ": no such file or directory"},
+		{name: "fmt", args: []string{"fmt"}, stdin: `{"b":[1,{}],"a":1.50}`, status: exitOK,
+			stdout: "{\n  \"b\": [\n    1,\n    {}\n  ],\n  \"a\": 1.50\n}\n"},
+		{name: "fmt indent", args: []string{"fmt", "-indent", "1"}, stdin: `[1]`, status: exitOK, stdout: "[\n 1\n]\n"},
+		{name: "fmt tab", args: []string{"fmt", "-tab"}, stdin: `[1]`, status: exitOK, stdout: "[\n\t1\n]\n"},
+		{name: "fmt minify", args: []string{"fmt", "-minify", other, bad, config}, status: exitFailed,
+			stdout: "{\"name\":\"web\",\"tags\":[]}\n" +
+				"{\"name\":\"api\",\"replicas\":3,\"price\":1.50,\"tags\":[\"a\",\"b\"],\"env\":{\"B\":\"1\",\"A\":\"2\"}}\n",
+			stderr: bad + ":3:7: "},
+		{name: "fmt negative indent", args: []string{"fmt", "-indent", "-1"}, status: exitUsage, stderr: "negative indent -1"},
+		{name: "get", args: []string{"get", "/env", config}, status: exitOK, stdout: "{\"B\":\"1\",\"A\":\"2\"}\n"},
+		{name: "get number", args: []string{"get", "/price", config}, status: exitOK, stdout: "1.50\n"},
+		{name: "get string", args: []string{"get", "/tags/1", config}, status: exitOK, stdout: "\"b\"\n"},
+		{name: "get raw string", args: []string{"get", "-r", "/a"}, stdin: `{"a": "x\ty"}`, status: exitOK, stdout: "x\ty\n"},
+		{name: "get several files", args: []string{"get", "-r", "/name", config, other}, status: exitOK,
+			stdout: config + ": api\n" + other + ": web\n"},
+		{name: "get not found", args: []string{"get", "/tags/2", config, other}, status: exitFailed,
+			stderr: config + `: JSON pointer "/tags/2"`},
+		{name: "get malformed before value", args: []string{"get", "/2"}, stdin: `[1 2 3]`, status: exitFailed,
+			stderr: "<stdin>:1:4: expected ',' or ']' after array element"},
+		{name: "get malformed nested", args: []string{"get", "/a/1"}, stdin: `{"a":[1 2 3]}`, status: exitFailed,
+			stderr: "<stdin>:1:9: "},
+		{name: "get malformed after value", args: []string{"get", "/a"}, stdin: `{"a":1, "b": garbage`, status: exitFailed,
+			stderr: "<stdin>:1:14: "},
+		{name: "get missing pointer", args: []string{"get"}, status: exitUsage, stderr: "missing pointer"},
+		{name: "get invalid pointer", args: []string{"get", "name"}, status: exitUsage, stderr: `invalid JSON pointer "name"`},
+		{name: "keys", args: []string{"keys", config}, status: exitOK, stdout: "name\nreplicas\nprice\ntags\nenv\n"},
+		{name: "keys malformed before value", args: []string{"keys", "-p", "/b"}, stdin: `{"a":1 "b":{}}`, status: exitFailed,
+			stderr: "<stdin>:1:8: "},
+		{name: "keys malformed after value", args: []string{"keys", "-p", "/a"}, stdin: `{"a":{"b":1}} x`, status: exitFailed,
+			stderr: "<stdin>:1:15: "},
+		{name: "keys pointer", args: []string{"keys", "-p", "/env", config}, status: exitOK, stdout: "B\nA\n"},
+		{name: "keys array", args: []string{"keys", "-p", "/tags", config, other}, status: exitOK,
+			stdout: config + ": 0\n" + config + ": 1\n"},
+		{name: "keys scalar", args: []string{"keys", "-p", "/name", config}, status: exitFailed,
+			stderr: config + ": a string has no keys"},
+		{name: "len", args: []string{"len", config}, status: exitOK, stdout: "5\n"},
+		{name: "len string", args: []string{"len", "-p", "/s"}, stdin: `{"s": "héllo"}`, status: exitOK, stdout: "5\n"},
+		{name: "len number", args: []string{"len", "-p", "/replicas", config}, status: exitFailed,
+			stderr: "a number has no length"},
+		{name: "no command", args: nil, status: exitUsage, stderr: "usage: jsonparse <command>"},
+		{name: "help", args: []string{"help"}, status: exitOK, stderr: "usage: jsonparse <command>"},
+		{name: "command help", args: []string{"get", "-h"}, status: exitOK, stderr: "usage: jsonparse get [-r] pointer"},
+		{name: "unknown command", args: []string{"query"}, status: exitUsage, stderr: `unknown command "query"`},
+		{name: "unknown flag", args: []string{"validate", "-x"}, status: exitUsage, stderr: "flag provided but not defined: -x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr strings.Builder
+			status := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			if status != tt.status {
+				t.Errorf("run() = %d, want %d (stderr %q)", status, tt.status, stderr.String())
+			}
+			if stdout.String() != tt.stdout {
+				t.Errorf("stdout = %q, want %q", stdout.String(), tt.stdout)
+			}
+			if !strings.Contains(stderr.String(), tt.stderr) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr.String(), tt.stderr)
+			}
+			if tt.stderr == "" && stderr.Len() > 0 {
+				t.Errorf("stderr = %q, want nothing", stderr.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package jsonl
+
+import (
+	"github.com/Ronit-Raj/json-parser/parser"
+	"github.com/Ronit-Raj/json-parser/scanner"
+)
+
+// LineError reports a line that holds no valid JSON document, or one that
+// does not fit the value it was decoded into.
+//
+// Err is the error parser.DecodeWithOptions returned for the line, such as
+// a scanner.SyntaxError or a *parser.TypeError, with its location moved from
+// the line to the whole input: the error for the third column of the fifth
+// line reads "5:3: ..." and its Position is a byte offset in the input.
+type LineError struct {
+	Line int    // the line number, starting at 1
+	Text string // the line, without its line break
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// relocate moves the location of err, an error decoding the line numbered
+// line that starts at byte offset start, to the whole input.
+func relocate(err error, line, start int) error {
+	move := func(loc *scanner.Location) {
+		loc.Position += start
+		if loc.Line > 0 {
+			loc.Line += line - 1
+		}
+	}
+	switch e := err.(type) {
+	case scanner.SyntaxError:
+		move(&e.Location)
+		return e
+	case *parser.TypeError:
+		move(&e.Location)
+	case *parser.NumberError:
+		move(&e.Location)
+	case *parser.UnknownFieldError:
+		move(&e.Location)
+	case *parser.DuplicateKeyError:
+		move(&e.Location)
+		move(&e.First)
+	case *parser.UnmarshalerError:
+		move(&e.Location)
+	}
+	return err
+}
@@ -0,0 +1,139 @@
+// Package jsonl reads and writes JSON Lines, also known as NDJSON: text in
+// which every line holds a complete JSON document of its own, such as one
+// record of a log export.
+package jsonl
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"iter"
+	"reflect"
+	"strings"
+
+	"github.com/Ronit-Raj/json-parser/parser"
+)
+
+// Reader reads one JSON document from each line of its input. Lines end with
+// "\n" or "\r\n", the last one may have no line break, and lines holding
+// only white space are ignored.
+//
+// A line that is not a valid document is reported as a *LineError. Since
+// every line stands on its own, this does not stop the Reader: the next call
+// moves on to the following line. Only an error reading the input is final.
+type Reader struct {
+	r    *bufio.Reader
+	opts []parser.Option
+
+	skip   bool
+	report func(*LineError)
+
+	line   int   // the number of the last line read
+	offset int   // the byte offset of the next line
+	err    error // the error that ended the input, io.EOF at its end
+}
+
+// NewReader returns a Reader that reads from r and decodes every line with
+// parser.DecodeWithOptions and opts.
+func NewReader(r io.Reader, opts ...parser.Option) *Reader {
+	return &Reader{r: bufio.NewReader(r), opts: opts}
+}
+
+// SkipInvalid makes the Reader pass over the lines that would be reported
+// as a *LineError. report, if not nil, is called with each of them, for
+// instance to log it or set it aside.
+func (r *Reader) SkipInvalid(report func(*LineError)) {
+	r.skip, r.report = true, report
+}
+
+// Line returns the number, starting at 1, of the line the last document or
+// *LineError came from.
+func (r *Reader) Line() int {
+	return r.line
+}
+
+// Decode reads the document on the next non-blank line and stores it in the
+// value pointed to by v, following the rules of parser.Decode. At the end
+// of the input it returns io.EOF.
+//
+// When invalid lines are skipped, v is reset to its zero value after each
+// of them, so no part of a skipped line is left in it.
+func (r *Reader) Decode(v any) error {
+	for {
+		text, start, err := r.readLine()
+		if err != nil {
+			return err
+		}
+		if strings.TrimLeft(text, " \t\r") == "" {
+			continue
+		}
+
+		err = parser.DecodeWithOptions(text, v, r.opts...)
+		var invalid *parser.InvalidDecodeError
+		if err == nil || errors.As(err, &invalid) {
+			return err
+		}
+		lineErr := &LineError{Line: r.line, Text: text, Err: relocate(err, r.line, start)}
+		if !r.skip {
+			return lineErr
+		}
+		if r.report != nil {
+			r.report(lineErr)
+		}
+		reflect.ValueOf(v).Elem().SetZero()
+	}
+}
+
+// Next reads the document on the next non-blank line into an interface
+// value. At the end of the input it returns nil, io.EOF.
+func (r *Reader) Next() (any, error) {
+	var v any
+	if err := r.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// All returns an iterator over the documents on the remaining lines, as
+// Next returns them. A *LineError is yielded with a nil document and the
+// iteration goes on; it ends at the end of the input or after yielding an
+// error reading it.
+func (r *Reader) All() iter.Seq2[any, error] {
+	return func(yield func(any, error) bool) {
+		for {
+			v, err := r.Next()
+			if err == io.EOF {
+				return
+			}
+			if !yield(v, err) {
+				return
+			}
+			var lineErr *LineError
+			if err != nil && !errors.As(err, &lineErr) {
+				return
+			}
+		}
+	}
+}
+
+// readLine returns the next line without its line break and the byte offset
+// at which it starts.
+func (r *Reader) readLine() (string, int, error) {
+	if r.err != nil {
+		return "", 0, r.err
+	}
+	text, err := r.r.ReadString('\n')
+	if err != nil {
+		r.err = err
+		// A read error may have cut the line short, so only a final line
+		// without a line break is kept.
+		if err != io.EOF || text == "" {
+			return "", 0, err
+		}
+	}
+	start := r.offset
+	r.offset += len(text)
+	r.line++
+	text = strings.TrimSuffix(text, "\n")
+	return strings.TrimSuffix(text, "\r"), start, nil
+}
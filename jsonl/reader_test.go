@@ -0,0 +1,229 @@
+package jsonl
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/Ronit-Raj/json-parser/parser"
+	"github.com/Ronit-Raj/json-parser/scanner"
+)
+
+// result is what Next returned for one line.
+type result struct {
+	value any
+	line  int
+	err   string // the error message, if any
+}
+
+// readAll calls Next on r until io.EOF or an error that is not a *LineError.
+func readAll(r *Reader) ([]result, error) {
+	var results []result
+	for {
+		v, err := r.Next()
+		if err == io.EOF {
+			return results, nil
+		}
+		var lineErr *LineError
+		if err != nil && !errors.As(err, &lineErr) {
+			return results, err
+		}
+		res := result{value: v, line: r.Line()}
+		if err != nil {
+			res.err = err.Error()
+		}
+		results = append(results, res)
+	}
+}
+
+func TestReader(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []result
+	}{
+		{name: "records", input: "{\"a\": 1}\n[2]\n\"three\"\n",
+			expected: []result{{value: map[string]any{"a": float64(1)}, line: 1}, {value: []any{float64(2)}, line: 2}, {value: "three", line: 3}}},
+		{name: "no final line break", input: "1\n2",
+			expected: []result{{value: float64(1), line: 1}, {value: float64(2), line: 2}}},
+		{name: "CRLF", input: "1\r\n2\r\n",
+			expected: []result{{value: float64(1), line: 1}, {value: float64(2), line: 2}}},
+		{name: "blank lines", input: "\n  \n1\n\t\r\n\n2\n\n",
+			expected: []result{{value: float64(1), line: 3}, {value: float64(2), line: 6}}},
+		{name: "empty", input: "", expected: nil},
+		{name: "invalid lines", input: "1\n[1,,]\n{\"a\": 1} 2\n3",
+			expected: []result{{value: float64(1), line: 1},
+				{line: 2, err: `2:4: unexpected "," looking for beginning of value`},
+				{line: 3, err: "3:10: "},
+				{value: float64(3), line: 4}}},
+		{name: "document across lines", input: "[1,\n2]",
+			expected: []result{{line: 1, err: "1:4: "}, {line: 2, err: "2:2: "}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readAll(NewReader(strings.NewReader(tt.input)))
+			if err != nil {
+				t.Fatalf("Next() error = %v", err)
+			}
+			if len(got) != len(tt.expected) {
+				t.Fatalf("Next() = %+v, want %+v", got, tt.expected)
+			}
+			for i, want := range tt.expected {
+				g := got[i]
+				if !reflect.DeepEqual(g.value, want.value) || g.line != want.line || !strings.HasPrefix(g.err, want.err) || (want.err == "") != (g.err == "") {
+					t.Errorf("record %d = %+v, want %+v", i, g, want)
+				}
+			}
+		})
+	}
+}
+
+func TestReaderLineError(t *testing.T) {
+	input := "{\"n\": 1}\n{\"n\": \"x\"}\n{\"n\": 1.5}\n"
+	r := NewReader(strings.NewReader(input))
+	var v struct{ N int }
+	if err := r.Decode(&v); err != nil || v.N != 1 {
+		t.Fatalf("Decode() = %+v, %v", v, err)
+	}
+
+	err := r.Decode(&v)
+	var lineErr *LineError
+	var typeErr *parser.TypeError
+	if !errors.As(err, &lineErr) || !errors.As(err, &typeErr) {
+		t.Fatalf("Decode() error = %v, want *LineError wrapping *parser.TypeError", err)
+	}
+	if lineErr.Line != 2 || lineErr.Text != `{"n": "x"}` {
+		t.Errorf("LineError = %+v", lineErr)
+	}
+	if typeErr.Line != 2 || typeErr.Column != 7 || typeErr.Position != 15 {
+		t.Errorf("location = %+v, want 2:7 at 15", typeErr.Location)
+	}
+	if want := `{"n": "x"}`; !strings.HasPrefix(typeErr.Excerpt, want) {
+		t.Errorf("Excerpt = %q, want the line", typeErr.Excerpt)
+	}
+
+	var numErr *parser.NumberError
+	if err := r.Decode(&v); !errors.As(err, &numErr) || numErr.Location.String() != "3:7" {
+		t.Errorf("Decode() error = %v, want *parser.NumberError at 3:7", err)
+	}
+
+	t.Run("same message as the parser", func(t *testing.T) {
+		line := `{"a": [1, 2,, 3]}`
+		_, err := NewReader(strings.NewReader(line)).Next()
+		want := parser.Decode(line, new(any))
+		if err == nil || want == nil || err.Error() != want.Error() {
+			t.Errorf("Next() error = %v, want %v", err, want)
+		}
+	})
+
+	t.Run("duplicate keys", func(t *testing.T) {
+		r := NewReader(strings.NewReader("\n{\"a\": 1, \"a\": 2}"), parser.DuplicateKeys(parser.RejectDuplicateKeys))
+		_, err := r.Next()
+		var dupErr *parser.DuplicateKeyError
+		if !errors.As(err, &dupErr) || dupErr.First.String() != "2:2" || dupErr.Location.String() != "2:10" {
+			t.Errorf("Next() error = %v, want a duplicate key at 2:10 first seen at 2:2", err)
+		}
+	})
+}
+
+func TestReaderSkipInvalid(t *testing.T) {
+	input := "{\"id\": 1, \"tags\": [\"a\"]}\n{\"id\": 2, \"tags\": [\"b\", }\n{\"id\": \"3\"}\n{\"id\": 4}\n"
+	r := NewReader(strings.NewReader(input))
+	var skipped []int
+	r.SkipInvalid(func(err *LineError) { skipped = append(skipped, err.Line) })
+
+	type record struct {
+		ID   int      `json:"id"`
+		Tags []string `json:"tags"`
+	}
+	var got []record
+	for {
+		var rec record
+		err := r.Decode(&rec)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		got = append(got, rec)
+	}
+	expected := []record{{ID: 1, Tags: []string{"a"}}, {ID: 4}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Decode() = %+v, want %+v", got, expected)
+	}
+	if !reflect.DeepEqual(skipped, []int{2, 3}) {
+		t.Errorf("skipped lines %v, want [2 3]", skipped)
+	}
+
+	t.Run("without report", func(t *testing.T) {
+		r := NewReader(strings.NewReader("x\n1\ny"))
+		r.SkipInvalid(nil)
+		got, err := readAll(r)
+		if err != nil || !reflect.DeepEqual(got, []result{{value: float64(1), line: 2}}) {
+			t.Errorf("Next() = %+v, %v", got, err)
+		}
+	})
+}
+
+func TestReaderAll(t *testing.T) {
+	input := "[1]\n{\"k\": 10000000000000000001}\noops\n"
+	var values []any
+	var errs []error
+	for v, err := range NewReader(iotest.OneByteReader(strings.NewReader(input)), parser.UseNumber()).All() {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		values = append(values, v)
+	}
+	expected := []any{[]any{parser.Number("1")}, map[string]any{"k": parser.Number("10000000000000000001")}}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("All() = %#v, want %#v", values, expected)
+	}
+	var syntaxErr scanner.SyntaxError
+	if len(errs) != 1 || !errors.As(errs[0], &syntaxErr) || syntaxErr.Line != 3 {
+		t.Errorf("All() errors = %v, want a syntax error on line 3", errs)
+	}
+
+	t.Run("break", func(t *testing.T) {
+		r := NewReader(strings.NewReader("1\n2\n3\n"))
+		for range r.All() {
+			break
+		}
+		if v, err := r.Next(); err != nil || v != float64(2) {
+			t.Errorf("Next() after break = %v, %v, want 2", v, err)
+		}
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		readErr := errors.New("connection reset")
+		r := NewReader(io.MultiReader(strings.NewReader("1\n2"), iotest.ErrReader(readErr)))
+		var got []any
+		var gotErr error
+		for v, err := range r.All() {
+			if err != nil {
+				gotErr = err
+				continue
+			}
+			got = append(got, v)
+		}
+		if !reflect.DeepEqual(got, []any{float64(1)}) || gotErr != readErr {
+			t.Errorf("All() = %v, %v, want [1] and the read error", got, gotErr)
+		}
+		if _, err := r.Next(); err != readErr {
+			t.Errorf("Next() after a read error = %v, want it again", err)
+		}
+	})
+}
+
+func TestReaderInvalidDecode(t *testing.T) {
+	var invalid *parser.InvalidDecodeError
+	if err := NewReader(strings.NewReader("1\n")).Decode(nil); !errors.As(err, &invalid) {
+		t.Errorf("Decode(nil) error = %v, want *parser.InvalidDecodeError", err)
+	}
+}
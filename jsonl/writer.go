@@ -0,0 +1,37 @@
+package jsonl
+
+import (
+	"io"
+	"strings"
+
+	"github.com/Ronit-Raj/json-parser/parser"
+)
+
+// Writer writes JSON documents to an output, each compacted onto a line of
+// its own.
+type Writer struct {
+	w io.Writer
+}
+
+// NewWriter returns a Writer that writes to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Write encodes v with parser.Encode and writes it followed by "\n", in a
+// single call to the underlying writer.
+func (w *Writer) Write(v any) error {
+	text, err := parser.Encode(v)
+	if err != nil {
+		return err
+	}
+	// Encode only leaves line breaks in the white space of a RawMessage,
+	// since those in strings are escaped.
+	if strings.ContainsAny(text, "\n\r") {
+		if text, err = parser.Compact(text); err != nil {
+			return err
+		}
+	}
+	_, err = io.WriteString(w.w, text+"\n")
+	return err
+}
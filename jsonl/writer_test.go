@@ -0,0 +1,91 @@
+package jsonl
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Ronit-Raj/json-parser/parser"
+)
+
+func TestWriter(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    any
+		expected string
+	}{
+		{name: "object", value: map[string]any{"b": 1, "a": []any{true, nil}}, expected: `{"a":[true,null],"b":1}` + "\n"},
+		{name: "string with line breaks", value: "a\nb\r ", expected: `"a\nb\r` + " \"\n"},
+		{name: "raw message", value: parser.RawMessage("{\n  \"a\": [1,\r\n 2]\n}"), expected: `{"a":[1,2]}` + "\n"},
+		{name: "struct", value: struct {
+			ID   int    `json:"id"`
+			Note string `json:"note,omitempty"`
+		}{ID: 7}, expected: `{"id":7}` + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := NewWriter(&sb).Write(tt.value); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if sb.String() != tt.expected {
+				t.Errorf("Write() wrote %q, want %q", sb.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestWriterErrors(t *testing.T) {
+	var sb strings.Builder
+	w := NewWriter(&sb)
+	if err := w.Write(math.NaN()); err == nil {
+		t.Errorf("Write(NaN) succeeded")
+	}
+	if err := w.Write(parser.RawMessage(`{"a"}`)); err == nil {
+		t.Errorf("Write() of an invalid RawMessage succeeded")
+	}
+	if sb.Len() != 0 {
+		t.Errorf("failed writes wrote %q", sb.String())
+	}
+
+	writeErr := errors.New("disk full")
+	if err := NewWriter(failingWriter{writeErr}).Write(1); err != writeErr {
+		t.Errorf("Write() error = %v, want %v", err, writeErr)
+	}
+}
+
+type failingWriter struct{ err error }
+
+func (w failingWriter) Write([]byte) (int, error) { return 0, w.err }
+
+func TestRoundTrip(t *testing.T) {
+	records := []any{
+		map[string]any{"msg": "line one\nline two", "level": "info"},
+		[]any{float64(1), "two", nil},
+		parser.RawMessage("[\n  3\n]"),
+	}
+	var sb strings.Builder
+	w := NewWriter(&sb)
+	for _, rec := range records {
+		if err := w.Write(rec); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if n := strings.Count(sb.String(), "\n"); n != len(records) {
+		t.Errorf("wrote %d lines, want %d: %q", n, len(records), sb.String())
+	}
+
+	got, err := readAll(NewReader(strings.NewReader(sb.String())))
+	if err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+	expected := []any{records[0], records[1], []any{float64(3)}}
+	for i, want := range expected {
+		if i >= len(got) || got[i].err != "" || !reflect.DeepEqual(got[i].value, want) {
+			t.Errorf("record %d = %+v, want %#v", i, got, want)
+		}
+	}
+}
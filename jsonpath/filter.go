@@ -0,0 +1,209 @@
+package jsonpath
+
+import (
+	"github.com/Ronit-Raj/json-parser/parser"
+	"github.com/Ronit-Raj/json-parser/patch"
+)
+
+// logicalExpr is the expression of a filter selector, or part of one.
+type logicalExpr interface {
+	// test evaluates the expression for the node current, which '@' refers
+	// to, in the document root, which '$' refers to.
+	test(current, root any) bool
+}
+
+type orExpr []logicalExpr
+
+func (e orExpr) test(current, root any) bool {
+	for _, expr := range e {
+		if expr.test(current, root) {
+			return true
+		}
+	}
+	return false
+}
+
+type andExpr []logicalExpr
+
+func (e andExpr) test(current, root any) bool {
+	for _, expr := range e {
+		if !expr.test(current, root) {
+			return false
+		}
+	}
+	return true
+}
+
+type notExpr struct {
+	expr logicalExpr
+}
+
+func (e notExpr) test(current, root any) bool {
+	return !e.expr.test(current, root)
+}
+
+// existsExpr holds if its query selects at least one node.
+type existsExpr struct {
+	query *filterQuery
+}
+
+func (e existsExpr) test(current, root any) bool {
+	return len(e.query.nodes(current, root)) > 0
+}
+
+// functionTest holds if its function returns true or at least one node.
+type functionTest struct {
+	fn *functionExpr
+}
+
+func (e functionTest) test(current, root any) bool {
+	switch result := e.fn.call(current, root).(type) {
+	case bool:
+		return result
+	case []node:
+		return len(result) > 0
+	}
+	return false
+}
+
+// comparable is one side of a comparison.
+type comparable interface {
+	// value returns the value to compare, or false if there is none, which
+	// RFC 9535 calls Nothing.
+	value(current, root any) (any, bool)
+}
+
+type literal struct {
+	v any
+}
+
+func (l literal) value(current, root any) (any, bool) {
+	return l.v, true
+}
+
+// filterQuery is a query inside a filter, relative to the current node
+// ('@') or to the root ('$').
+type filterQuery struct {
+	relative bool
+	segments []segment
+}
+
+func (q *filterQuery) nodes(current, root any) []node {
+	start := root
+	if q.relative {
+		start = current
+	}
+	return evaluate(q.segments, start, root, false)
+}
+
+// singular reports whether q can select at most one node: it has only name
+// and index selectors, one per segment, and no descendant segments.
+func (q *filterQuery) singular() bool {
+	for _, seg := range q.segments {
+		if seg.descendant || len(seg.selectors) != 1 {
+			return false
+		}
+		switch seg.selectors[0].(type) {
+		case nameSelector, indexSelector:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// singularQuery is a singular filterQuery used as a comparable. It walks
+// the document directly instead of collecting nodes.
+type singularQuery struct {
+	query *filterQuery
+}
+
+func (q singularQuery) value(current, root any) (any, bool) {
+	v := root
+	if q.query.relative {
+		v = current
+	}
+	for _, seg := range q.query.segments {
+		switch sel := seg.selectors[0].(type) {
+		case nameSelector:
+			var ok bool
+			if v, ok = member(v, string(sel)); !ok {
+				return nil, false
+			}
+		case indexSelector:
+			a, ok := v.([]any)
+			i := int(sel)
+			if i < 0 {
+				i += len(a)
+			}
+			if !ok || i < 0 || i >= len(a) {
+				return nil, false
+			}
+			v = a[i]
+		}
+	}
+	return v, true
+}
+
+// comparisonExpr compares two values as RFC 9535 section 2.3.5.2.2
+// describes.
+type comparisonExpr struct {
+	op          string
+	left, right comparable
+}
+
+func (e comparisonExpr) test(current, root any) bool {
+	a, aok := e.left.value(current, root)
+	b, bok := e.right.value(current, root)
+	switch e.op {
+	case "==":
+		return equal(a, aok, b, bok)
+	case "!=":
+		return !equal(a, aok, b, bok)
+	case "<":
+		return less(a, aok, b, bok)
+	case "<=":
+		return less(a, aok, b, bok) || equal(a, aok, b, bok)
+	case ">":
+		return less(b, bok, a, aok)
+	case ">=":
+		return less(b, bok, a, aok) || equal(a, aok, b, bok)
+	}
+	return false
+}
+
+// equal compares two values, either of which may be Nothing. Nothing only
+// equals Nothing.
+func equal(a any, aok bool, b any, bok bool) bool {
+	if !aok || !bok {
+		return aok == bok
+	}
+	return patch.Equal(a, b)
+}
+
+// less orders numbers by value and strings by their code points. Any other
+// values are unordered.
+func less(a any, aok bool, b any, bok bool) bool {
+	if !aok || !bok {
+		return false
+	}
+	if as, ok := a.(string); ok {
+		bs, ok := b.(string)
+		return ok && as < bs
+	}
+	af, aIsNumber := number(a)
+	bf, bIsNumber := number(b)
+	return aIsNumber && bIsNumber && af < bf
+}
+
+// number returns the value of a number in a decoded document.
+func number(v any) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case parser.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,63 @@
+package jsonpath
+
+import (
+	"testing"
+
+	"github.com/Ronit-Raj/json-parser/parser"
+)
+
+func TestComparison(t *testing.T) {
+	nothing := struct{}{}
+	tests := []struct {
+		left, right any
+		equal, less bool
+	}{
+		{left: nothing, right: nothing, equal: true},
+		{left: nothing, right: 1.0},
+		{left: nil, right: nothing},
+		{left: nil, right: nil, equal: true},
+		{left: 1.0, right: 2.0, less: true},
+		{left: parser.Number("1e0"), right: 1.0, equal: true},
+		{left: parser.Number("1"), right: parser.Number("2.5"), less: true},
+		{left: "a", right: "b", less: true},
+		{left: "B", right: "a", less: true},
+		{left: "1", right: 2.0},
+		{left: true, right: false},
+		{left: []any{1.0}, right: []any{1.0}, equal: true},
+		{left: map[string]any{"a": 1.0}, right: map[string]any{"a": 1.0}, equal: true},
+	}
+
+	for _, tt := range tests {
+		a, aok := tt.left, tt.left != nothing
+		b, bok := tt.right, tt.right != nothing
+		if got := equal(a, aok, b, bok); got != tt.equal {
+			t.Errorf("equal(%v, %v) = %v, want %v", tt.left, tt.right, got, tt.equal)
+		}
+		if got := less(a, aok, b, bok); got != tt.less {
+			t.Errorf("less(%v, %v) = %v, want %v", tt.left, tt.right, got, tt.less)
+		}
+	}
+}
+
+func TestSingular(t *testing.T) {
+	tests := []struct {
+		query    string
+		singular bool
+	}{
+		{query: `$`, singular: true},
+		{query: `$.a[0]['b'][-1]`, singular: true},
+		{query: `$.*`},
+		{query: `$..a`},
+		{query: `$[0, 1]`},
+		{query: `$[0:1]`},
+		{query: `$[?@]`},
+	}
+
+	for _, tt := range tests {
+		p := MustCompile(tt.query)
+		q := &filterQuery{segments: p.segments}
+		if got := q.singular(); got != tt.singular {
+			t.Errorf("singular(%s) = %v, want %v", tt.query, got, tt.singular)
+		}
+	}
+}
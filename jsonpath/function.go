@@ -0,0 +1,249 @@
+package jsonpath
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Ronit-Raj/json-parser/parser"
+)
+
+// funcType is one of the types of function parameters and results of
+// RFC 9535 section 2.4.1.
+type funcType int8
+
+const (
+	valueType   funcType = iota // a JSON value or Nothing
+	logicalType                 // true or false
+	nodesType                   // the nodes a query selects
+)
+
+// function is a function extension a filter may call.
+type function struct {
+	params []funcType
+	result funcType
+	// call computes the result from the arguments. A valueType argument or
+	// result is a jsonValue, a logicalType one a bool and a nodesType one a
+	// []node.
+	call func(args []any) any
+	// prepare, if set, is called once a call to the function is parsed.
+	prepare func(call *functionExpr)
+}
+
+// jsonValue is a valueType argument or result. If ok is false it is
+// Nothing.
+type jsonValue struct {
+	v  any
+	ok bool
+}
+
+// functions are the function extensions RFC 9535 defines.
+var functions = map[string]*function{
+	"length": {params: []funcType{valueType}, result: valueType, call: length},
+	"count":  {params: []funcType{nodesType}, result: valueType, call: count},
+	"match":  {params: []funcType{valueType, valueType}, result: logicalType, call: match(true), prepare: compileLiteral(true)},
+	"search": {params: []funcType{valueType, valueType}, result: logicalType, call: match(false), prepare: compileLiteral(false)},
+	"value":  {params: []funcType{nodesType}, result: valueType, call: value},
+}
+
+// functionExpr is a call to a function in a filter.
+type functionExpr struct {
+	name string
+	fn   *function
+	// args are comparables, logicalExprs or node sources, by the type of
+	// the parameter.
+	args []any
+	// re is the regular expression of a call to match or search whose
+	// pattern is a literal, compiled in advance. It is nil if the pattern
+	// is not a valid I-Regexp.
+	re       *regexp.Regexp
+	compiled bool
+}
+
+// nodeSource is an argument for a nodesType parameter.
+type nodeSource interface {
+	nodes(current, root any) []node
+}
+
+func (f *functionExpr) call(current, root any) any {
+	args := make([]any, len(f.args))
+	for i, arg := range f.args {
+		switch f.fn.params[i] {
+		case valueType:
+			v, ok := arg.(comparable).value(current, root)
+			args[i] = jsonValue{v, ok}
+		case logicalType:
+			args[i] = arg.(logicalExpr).test(current, root)
+		case nodesType:
+			args[i] = arg.(nodeSource).nodes(current, root)
+		}
+	}
+	if f.compiled {
+		// The pattern is known, so pass the compiled expression instead.
+		args[1] = f.re
+	}
+	return f.fn.call(args)
+}
+
+// value makes a call to a function returning a valueType a comparable.
+func (f *functionExpr) value(current, root any) (any, bool) {
+	result := f.call(current, root).(jsonValue)
+	return result.v, result.ok
+}
+
+// nodes makes a call to a function returning a nodesType a nodeSource.
+func (f *functionExpr) nodes(current, root any) []node {
+	return f.call(current, root).([]node)
+}
+
+// length returns the number of characters in a string, elements in an
+// array or members in an object, and Nothing for anything else.
+func length(args []any) any {
+	switch v := args[0].(jsonValue).v; v := v.(type) {
+	case string:
+		return jsonValue{float64(utf8.RuneCountInString(v)), true}
+	case []any:
+		return jsonValue{float64(len(v)), true}
+	case map[string]any:
+		return jsonValue{float64(len(v)), true}
+	case *parser.OrderedObject:
+		return jsonValue{float64(v.Len()), true}
+	}
+	return jsonValue{}
+}
+
+// count returns the number of nodes.
+func count(args []any) any {
+	return jsonValue{float64(len(args[0].([]node))), true}
+}
+
+// value returns the value of the only node, or Nothing if there is not
+// exactly one.
+func value(args []any) any {
+	nodes := args[0].([]node)
+	if len(nodes) != 1 {
+		return jsonValue{}
+	}
+	return jsonValue{nodes[0].value, true}
+}
+
+// match returns the function that tests a string against a regular
+// expression: match if full is set, which must match the whole string, or
+// search, which may match any part of it.
+func match(full bool) func(args []any) any {
+	return func(args []any) any {
+		s, ok := args[0].(jsonValue).v.(string)
+		if !ok {
+			return false
+		}
+		var re *regexp.Regexp
+		switch pattern := args[1].(type) {
+		case *regexp.Regexp:
+			re = pattern
+		case jsonValue:
+			p, ok := pattern.v.(string)
+			if !ok {
+				return false
+			}
+			re = compileIRegexp(p, full)
+		}
+		return re != nil && re.MatchString(s)
+	}
+}
+
+// compileLiteral returns the prepare function of match or search, which
+// compiles a literal pattern once.
+func compileLiteral(full bool) func(call *functionExpr) {
+	return func(call *functionExpr) {
+		if l, ok := call.args[1].(literal); ok {
+			call.compiled = true
+			if pattern, ok := l.v.(string); ok {
+				call.re = compileIRegexp(pattern, full)
+			}
+		}
+	}
+}
+
+// compileIRegexp compiles an I-Regexp (RFC 9485), anchored at both ends if
+// full is set. It returns nil if pattern is not a valid I-Regexp.
+//
+// I-Regexp is close to the syntax of package regexp, but '.' does not match
+// "\r" either, '^' and '$' are ordinary characters, and there are no
+// shorthands such as \d, no anchors and no non-capturing groups or flags.
+func compileIRegexp(pattern string, full bool) *regexp.Regexp {
+	var sb strings.Builder
+	if full {
+		sb.WriteString(`\A(?:`)
+	}
+	inClass := false
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '\\':
+			if i+1 == len(pattern) {
+				return nil
+			}
+			i++
+			switch e := pattern[i]; e {
+			case '(', ')', '*', '+', '-', '.', '?', '[', '\\', ']', '^', '{', '|', '}', 'n', 'r', 't':
+				sb.WriteByte('\\')
+				sb.WriteByte(e)
+			case 'p', 'P':
+				// Category escapes such as \p{Lu} are written the same.
+				end := strings.IndexByte(pattern[i:], '}')
+				if i+1 == len(pattern) || pattern[i+1] != '{' || end < 0 {
+					return nil
+				}
+				sb.WriteString(`\` + pattern[i:i+end+1])
+				i += end
+			default:
+				return nil
+			}
+		case inClass:
+			if c == ']' {
+				inClass = false
+			}
+			if c == '[' {
+				// Class subtraction and nested classes are not I-Regexp.
+				return nil
+			}
+			sb.WriteByte(c)
+		case c == '[':
+			inClass = true
+			sb.WriteByte(c)
+			if i+1 < len(pattern) && pattern[i+1] == '^' {
+				sb.WriteByte('^')
+				i++
+			}
+			if i+1 < len(pattern) && pattern[i+1] == ']' {
+				// A class cannot be empty, and ']' must be escaped in it.
+				return nil
+			}
+		case c == '.':
+			sb.WriteString(`[^\n\r]`)
+		case c == '^' || c == '$':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case c == '(' && i+1 < len(pattern) && pattern[i+1] == '?':
+			return nil
+		case (c == '?' || c == '*' || c == '+') && i > 0 && strings.IndexByte("?*+}", pattern[i-1]) >= 0 &&
+			(i < 2 || pattern[i-2] != '\\'):
+			// A quantifier cannot follow another, which also rules out
+			// lazy quantifiers such as *?.
+			return nil
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	if inClass {
+		return nil
+	}
+	if full {
+		sb.WriteString(`)\z`)
+	}
+	re, err := regexp.Compile(sb.String())
+	if err != nil {
+		return nil
+	}
+	return re
+}
@@ -0,0 +1,81 @@
+package jsonpath
+
+import "testing"
+
+func TestCompileIRegexp(t *testing.T) {
+	tests := []struct {
+		pattern string
+		full    bool
+		input   string
+		valid   bool
+		matches bool
+	}{
+		{pattern: `a.c`, full: true, input: "abc", valid: true, matches: true},
+		{pattern: `a.c`, full: true, input: "a\rc", valid: true, matches: false},
+		{pattern: `a.c`, full: true, input: "a\nc", valid: true, matches: false},
+		{pattern: `b`, full: true, input: "abc", valid: true, matches: false},
+		{pattern: `b`, full: false, input: "abc", valid: true, matches: true},
+		{pattern: `a|bc`, full: true, input: "bc", valid: true, matches: true},
+		{pattern: `a|b`, full: true, input: "ab", valid: true, matches: false},
+		{pattern: `^a$`, full: true, input: "^a$", valid: true, matches: true},
+		{pattern: `[^a-c]+`, full: true, input: "xyz", valid: true, matches: true},
+		{pattern: `[a\]]{2}`, full: true, input: "a]", valid: true, matches: true},
+		{pattern: `\p{Lu}\P{Lu}`, full: true, input: "Ab", valid: true, matches: true},
+		{pattern: `\.\n`, full: true, input: ".\n", valid: true, matches: true},
+		{pattern: `x{2,3}`, full: true, input: "xxx", valid: true, matches: true},
+		{pattern: `\d`},
+		{pattern: `\w+`},
+		{pattern: `\b`},
+		{pattern: `(?i)a`},
+		{pattern: `a(?:b)`},
+		{pattern: `a*?`},
+		{pattern: `a++`},
+		{pattern: `[]`},
+		{pattern: `[a[b]]`},
+		{pattern: `[ab`},
+		{pattern: `a\`},
+		{pattern: `\p{Lu`},
+		{pattern: `(a`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			re := compileIRegexp(tt.pattern, tt.full)
+			if (re != nil) != tt.valid {
+				t.Fatalf("compileIRegexp(%q) = %v, want valid %v", tt.pattern, re, tt.valid)
+			}
+			if re != nil && re.MatchString(tt.input) != tt.matches {
+				t.Errorf("%q matches %q = %v, want %v", tt.pattern, tt.input, !tt.matches, tt.matches)
+			}
+		})
+	}
+}
+
+func TestFunctionTypes(t *testing.T) {
+	tests := []struct {
+		query string
+		valid bool
+	}{
+		{query: `$[?length(@) < 3]`, valid: true},
+		{query: `$[?length(@.*) < 3]`},
+		{query: `$[?count(@.*) == 1]`, valid: true},
+		{query: `$[?count(1) == 1]`},
+		{query: `$[?count(value(@.a)) == 1]`},
+		{query: `$[?match(@.timezone, 'Europe/.*')]`, valid: true},
+		{query: `$[?match(@.timezone, 'Europe/.*') == true]`},
+		{query: `$[?value(@..color) == "red"]`, valid: true},
+		{query: `$[?value(@..color)]`},
+		{query: `$[?length(value(@.a)) > 1]`, valid: true},
+		{query: `$[?match(@.a, $.pattern)]`, valid: true},
+		{query: `$[?search(@.a, 'x') && !match(@.b, 'y')]`, valid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := Compile(tt.query)
+			if (err == nil) != tt.valid {
+				t.Errorf("Compile() error = %v, want valid %v", err, tt.valid)
+			}
+		})
+	}
+}
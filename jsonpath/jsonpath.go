@@ -0,0 +1,342 @@
+// Package jsonpath implements JSONPath (RFC 9535), a query language for
+// selecting values from a JSON document, such as
+// $.students[?@.attendance < 0.9].name.
+package jsonpath
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Ronit-Raj/json-parser/parser"
+)
+
+// Path is a compiled JSONPath query. It is safe for concurrent use.
+type Path struct {
+	text     string
+	segments []segment
+}
+
+// Node is a value a query selected, together with the normalized path that
+// locates it in the document, such as $['students'][2]['name'].
+type Node struct {
+	Path  string
+	Value any
+}
+
+// Compile parses a JSONPath query. An invalid query, including one that
+// RFC 9535 rejects because a function is called with arguments of the wrong
+// type, gives a scanner.SyntaxError.
+func Compile(query string) (*Path, error) {
+	p := &queryParser{text: query}
+	segments, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	return &Path{text: query, segments: segments}, nil
+}
+
+// MustCompile is Compile for queries known to be valid. It panics if query
+// is not.
+func MustCompile(query string) *Path {
+	p, err := Compile(query)
+	if err != nil {
+		panic(`jsonpath: Compile(` + strconv.Quote(query) + `): ` + err.Error())
+	}
+	return p
+}
+
+// Select compiles query and runs it on doc. See Path.Select.
+func Select(doc any, query string) ([]Node, error) {
+	p, err := Compile(query)
+	if err != nil {
+		return nil, err
+	}
+	return p.Select(doc), nil
+}
+
+// String returns the query p was compiled from.
+func (p *Path) String() string {
+	return p.text
+}
+
+// Select runs the query on doc, a document as parser.Decode builds it into
+// an interface value, and returns the selected nodes in order. RFC 9535
+// leaves the order of object members open; Select visits the members of a
+// map in sorted order and those of a *parser.OrderedObject in their own.
+func (p *Path) Select(doc any) []Node {
+	nodes := evaluate(p.segments, doc, doc, true)
+	result := make([]Node, len(nodes))
+	for i, n := range nodes {
+		result[i] = Node{Path: n.loc.String(), Value: n.value}
+	}
+	return result
+}
+
+// Values is Select without the paths, which it does not spend time on.
+func (p *Path) Values(doc any) []any {
+	nodes := evaluate(p.segments, doc, doc, false)
+	values := make([]any, len(nodes))
+	for i, n := range nodes {
+		values[i] = n.value
+	}
+	return values
+}
+
+// node is a value selected while evaluating a query. loc is nil unless the
+// evaluation keeps track of paths.
+type node struct {
+	value any
+	loc   *location
+}
+
+// location is one step of the path to a node, linked to the step before it.
+type location struct {
+	parent  *location
+	name    string
+	index   int // used if isIndex is set
+	isIndex bool
+}
+
+// String returns the normalized path of l, as RFC 9535 section 2.7 defines
+// it.
+func (l *location) String() string {
+	var steps []*location
+	for ; l != nil; l = l.parent {
+		steps = append(steps, l)
+	}
+	var sb strings.Builder
+	sb.WriteByte('$')
+	for _, step := range slices.Backward(steps) {
+		if step.isIndex {
+			fmt.Fprintf(&sb, "[%d]", step.index)
+			continue
+		}
+		sb.WriteString("['")
+		for _, r := range step.name {
+			switch r {
+			case '\b':
+				sb.WriteString(`\b`)
+			case '\f':
+				sb.WriteString(`\f`)
+			case '\n':
+				sb.WriteString(`\n`)
+			case '\r':
+				sb.WriteString(`\r`)
+			case '\t':
+				sb.WriteString(`\t`)
+			case '\'':
+				sb.WriteString(`\'`)
+			case '\\':
+				sb.WriteString(`\\`)
+			default:
+				if r < 0x20 {
+					fmt.Fprintf(&sb, `\u%04x`, r)
+				} else {
+					sb.WriteRune(r)
+				}
+			}
+		}
+		sb.WriteString("']")
+	}
+	return sb.String()
+}
+
+// evaluator runs the segments of one query.
+type evaluator struct {
+	root  any
+	paths bool
+}
+
+// evaluate applies segments to start in turn. root is what '$' refers to in
+// filters.
+func evaluate(segments []segment, start, root any, paths bool) []node {
+	e := &evaluator{root: root, paths: paths}
+	nodes := []node{{value: start}}
+	for _, seg := range segments {
+		var next []node
+		for _, n := range nodes {
+			next = e.segment(seg, n, next)
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// segment is a child segment, such as .name or [0, 1], or a descendant
+// segment such as ..name, which applies its selectors to a node and all of
+// its descendants.
+type segment struct {
+	descendant bool
+	selectors  []selector
+}
+
+// segment appends the nodes seg selects from n to out.
+func (e *evaluator) segment(seg segment, n node, out []node) []node {
+	for _, sel := range seg.selectors {
+		out = sel.apply(e, n, out)
+	}
+	if seg.descendant {
+		e.children(n, func(child node) {
+			out = e.segment(seg, child, out)
+		})
+	}
+	return out
+}
+
+// children calls f with each member of an object or element of an array,
+// in order.
+func (e *evaluator) children(n node, f func(node)) {
+	switch v := n.value.(type) {
+	case []any:
+		for i, value := range v {
+			f(node{value: value, loc: e.index(n, i)})
+		}
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			f(node{value: v[key], loc: e.name(n, key)})
+		}
+	case *parser.OrderedObject:
+		for _, member := range v.Members() {
+			f(node{value: member.Value, loc: e.name(n, member.Key)})
+		}
+	}
+}
+
+// name returns the location of the member name of n.
+func (e *evaluator) name(n node, name string) *location {
+	if !e.paths {
+		return nil
+	}
+	return &location{parent: n.loc, name: name}
+}
+
+// index returns the location of the element i of n.
+func (e *evaluator) index(n node, i int) *location {
+	if !e.paths {
+		return nil
+	}
+	return &location{parent: n.loc, index: i, isIndex: true}
+}
+
+// selector is one of the selectors of a segment.
+type selector interface {
+	// apply appends the nodes the selector selects from n to out.
+	apply(e *evaluator, n node, out []node) []node
+}
+
+// nameSelector selects the member of an object with the name.
+type nameSelector string
+
+func (s nameSelector) apply(e *evaluator, n node, out []node) []node {
+	if v, ok := member(n.value, string(s)); ok {
+		out = append(out, node{value: v, loc: e.name(n, string(s))})
+	}
+	return out
+}
+
+// member returns the member name of the object v.
+func member(v any, name string) (any, bool) {
+	switch o := v.(type) {
+	case map[string]any:
+		value, ok := o[name]
+		return value, ok
+	case *parser.OrderedObject:
+		return o.Get(name)
+	}
+	return nil, false
+}
+
+// wildcardSelector selects every member of an object or element of an
+// array.
+type wildcardSelector struct{}
+
+func (wildcardSelector) apply(e *evaluator, n node, out []node) []node {
+	e.children(n, func(child node) {
+		out = append(out, child)
+	})
+	return out
+}
+
+// indexSelector selects an element of an array. A negative index counts
+// from the end.
+type indexSelector int
+
+func (s indexSelector) apply(e *evaluator, n node, out []node) []node {
+	if a, ok := n.value.([]any); ok {
+		i := int(s)
+		if i < 0 {
+			i += len(a)
+		}
+		if i >= 0 && i < len(a) {
+			out = append(out, node{value: a[i], loc: e.index(n, i)})
+		}
+	}
+	return out
+}
+
+// sliceSelector selects the elements of an array from start up to but not
+// including end, every step elements. Missing bounds default as in RFC 9535
+// section 2.3.4.2.2.
+type sliceSelector struct {
+	start, end, step *int
+}
+
+func (s sliceSelector) apply(e *evaluator, n node, out []node) []node {
+	a, ok := n.value.([]any)
+	if !ok {
+		return out
+	}
+	length := len(a)
+	step := 1
+	if s.step != nil {
+		step = *s.step
+	}
+	if step == 0 {
+		return out
+	}
+	normalize := func(bound *int, def int) int {
+		if bound == nil {
+			return def
+		}
+		if *bound < 0 {
+			return length + *bound
+		}
+		return *bound
+	}
+	if step > 0 {
+		lower := min(max(normalize(s.start, 0), 0), length)
+		upper := min(max(normalize(s.end, length), 0), length)
+		for i := lower; i < upper; i += step {
+			out = append(out, node{value: a[i], loc: e.index(n, i)})
+		}
+		return out
+	}
+	upper := min(max(normalize(s.start, length-1), -1), length-1)
+	lower := min(max(normalize(s.end, -length-1), -1), length-1)
+	for i := upper; lower < i; i += step {
+		out = append(out, node{value: a[i], loc: e.index(n, i)})
+	}
+	return out
+}
+
+// filterSelector selects the members of an object or elements of an array
+// for which its expression holds.
+type filterSelector struct {
+	expr logicalExpr
+}
+
+func (s filterSelector) apply(e *evaluator, n node, out []node) []node {
+	e.children(n, func(child node) {
+		if s.expr.test(child.value, e.root) {
+			out = append(out, child)
+		}
+	})
+	return out
+}
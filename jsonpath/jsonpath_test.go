@@ -0,0 +1,205 @@
+package jsonpath
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Ronit-Raj/json-parser/parser"
+	"github.com/Ronit-Raj/json-parser/patch"
+	"github.com/Ronit-Raj/json-parser/scanner"
+)
+
+// selectorCase is a case in the format of the JSONPath Compliance Test
+// Suite. Where the order of the nodes is not fixed, Results and
+// ResultsPaths hold every order allowed instead of Result and ResultPaths.
+type selectorCase struct {
+	Name            string     `json:"name"`
+	Selector        string     `json:"selector"`
+	Document        any        `json:"document"`
+	Result          []any      `json:"result"`
+	ResultPaths     []string   `json:"result_paths"`
+	Results         [][]any    `json:"results"`
+	ResultsPaths    [][]string `json:"results_paths"`
+	InvalidSelector bool       `json:"invalid_selector"`
+}
+
+// runSelectorCases runs the cases in file, other than those in skip, which
+// maps their names to the reason they are skipped.
+func runSelectorCases(t *testing.T, file string, skip map[string]string) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var suite struct {
+		Tests []selectorCase `json:"tests"`
+	}
+	if err := parser.Decode(string(data), &suite); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	for _, tt := range suite.Tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			if reason, ok := skip[tt.Name]; ok {
+				t.Skip(reason)
+			}
+			p, err := Compile(tt.Selector)
+			if tt.InvalidSelector {
+				if err == nil {
+					t.Fatalf("Compile(%q) succeeded, want an error", tt.Selector)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Compile(%q) error = %v", tt.Selector, err)
+			}
+			nodes := p.Select(tt.Document)
+			values := make([]any, len(nodes))
+			paths := make([]string, len(nodes))
+			for i, n := range nodes {
+				values[i], paths[i] = n.Value, n.Path
+			}
+			results, resultsPaths := tt.Results, tt.ResultsPaths
+			if results == nil {
+				results, resultsPaths = [][]any{tt.Result}, [][]string{tt.ResultPaths}
+			}
+			matched := slices.IndexFunc(results, func(result []any) bool {
+				return patch.Equal(values, append([]any{}, result...))
+			})
+			if matched < 0 {
+				t.Fatalf("Select() values = %v, want %v", values, results[0])
+			}
+			if matched < len(resultsPaths) && resultsPaths[matched] != nil && !slices.Equal(paths, resultsPaths[matched]) {
+				t.Errorf("Select() paths = %q, want %q", paths, resultsPaths[matched])
+			}
+			if got := p.Values(tt.Document); !patch.Equal(got, values) {
+				t.Errorf("Values() = %v, want %v", got, values)
+			}
+		})
+	}
+}
+
+// TestSelectorCases runs testdata/cases.json, cases written for this
+// package, many after the examples of RFC 9535.
+func TestSelectorCases(t *testing.T) {
+	runSelectorCases(t, "testdata/cases.json", nil)
+}
+
+// complianceSkipped names the cases of the compliance suite that are skipped
+// on purpose, each with the reason.
+var complianceSkipped = map[string]string{}
+
+// TestCompliance runs testdata/cts.json from the JSONPath Compliance Test
+// Suite, https://github.com/jsonpath-standard/jsonpath-compliance-test-suite.
+// To update it, replace the file with cts.json from the suite's main branch.
+func TestCompliance(t *testing.T) {
+	const file = "testdata/cts.json"
+	if _, err := os.Stat(file); errors.Is(err, os.ErrNotExist) {
+		t.Skipf("%s is missing; copy cts.json from the compliance suite there", file)
+	}
+	runSelectorCases(t, file, complianceSkipped)
+}
+
+func TestSelect(t *testing.T) {
+	var doc any
+	input := `{"students": [{"name": "Ada", "attendance": 0.95}, {"name": "Alan", "attendance": 0.85}]}`
+	if err := parser.Decode(input, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	nodes, err := Select(doc, `$.students[?(@.attendance < 0.9)].name`)
+	if err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+	want := []Node{{Path: "$['students'][1]['name']", Value: "Alan"}}
+	if !slices.Equal(nodes, want) {
+		t.Errorf("Select() = %v, want %v", nodes, want)
+	}
+
+	if _, err := Select(doc, `$.students[`); err == nil {
+		t.Errorf("Select() of an invalid query succeeded")
+	}
+}
+
+func TestOrderedObject(t *testing.T) {
+	var doc any
+	err := parser.DecodeWithOptions(`{"b": 2, "a": {"y": 1, "x": 2}, "c": 3}`, &doc, parser.UseOrderedObject())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var paths []string
+	for _, n := range MustCompile(`$..*`).Select(doc) {
+		paths = append(paths, n.Path)
+	}
+	want := []string{"$['b']", "$['a']", "$['c']", "$['a']['y']", "$['a']['x']"}
+	if !slices.Equal(paths, want) {
+		t.Errorf("Select() paths = %q, want %q", paths, want)
+	}
+
+	got := MustCompile(`$[?@.x == 2].y`).Values(doc)
+	if !patch.Equal(got, []any{1.0}) {
+		t.Errorf("Values() = %v, want [1]", got)
+	}
+}
+
+func TestNumbers(t *testing.T) {
+	var doc any
+	err := parser.DecodeWithOptions(`[1.0, 2, 12345678901234567890, "2"]`, &doc, parser.UseNumber())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := MustCompile(`$[?@ >= 1 && @ < 2.5]`).Values(doc)
+	want := []any{parser.Number("1.0"), parser.Number("2")}
+	if !slices.Equal(got, want) {
+		t.Errorf("Values() = %v, want %v", got, want)
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		query    string
+		position int
+		message  string
+	}{
+		{query: `a`, position: 0, message: "query must start with '$'"},
+		{query: `$.a b`, position: 3, message: "unexpected"},
+		{query: `$[01]`, position: 2, message: `invalid integer "01"`},
+		{query: `$['a`, position: 4, message: "unterminated string"},
+		{query: `$[?@.* == 1]`, position: 3, message: "at most one node can be compared"},
+		{query: `$[?length(@.*) < 3]`, position: 10, message: "argument of length()"},
+		{query: `$[?foo(@)]`, position: 3, message: "unknown function"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := Compile(tt.query)
+			var syntaxErr scanner.SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Compile() error = %v, want a SyntaxError", err)
+			}
+			if syntaxErr.Position != tt.position {
+				t.Errorf("error position = %d, want %d (%v)", syntaxErr.Position, tt.position, err)
+			}
+			if !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("Compile() error = %v, want it to mention %q", err, tt.message)
+			}
+		})
+	}
+}
+
+func TestMustCompile(t *testing.T) {
+	if p := MustCompile(`$.a`); p.String() != `$.a` {
+		t.Errorf("String() = %q, want %q", p.String(), `$.a`)
+	}
+
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(string), `jsonpath: Compile("$[")`) {
+			t.Errorf("MustCompile() panic = %v", r)
+		}
+	}()
+	MustCompile(`$[`)
+}
@@ -0,0 +1,606 @@
+package jsonpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/Ronit-Raj/json-parser/scanner"
+)
+
+// maxInt is the largest index or slice bound a query may contain: RFC 9535
+// limits them to the integers a float64 holds exactly (I-JSON).
+const maxInt = 1<<53 - 1
+
+// queryParser parses the text of one query.
+type queryParser struct {
+	text string
+	pos  int
+}
+
+// parse parses a whole query, which must start with '$'.
+func (p *queryParser) parse() ([]segment, error) {
+	if !p.consume("$") {
+		return nil, p.errorf("query must start with '$'")
+	}
+	segments, err := p.segments()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.text) {
+		return nil, p.errorf("unexpected %s", p.describe())
+	}
+	return segments, nil
+}
+
+// segments parses the segments after '$' or '@', which blank space may
+// separate.
+func (p *queryParser) segments() ([]segment, error) {
+	var segments []segment
+	for {
+		start := p.pos
+		p.skipBlank()
+		if !p.peek('.') && !p.peek('[') {
+			p.pos = start
+			return segments, nil
+		}
+		seg, err := p.segment()
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, seg)
+	}
+}
+
+func (p *queryParser) segment() (segment, error) {
+	switch {
+	case p.consume(".."):
+		if p.peek('[') {
+			selectors, err := p.bracketed()
+			return segment{descendant: true, selectors: selectors}, err
+		}
+		sel, err := p.shorthand()
+		return segment{descendant: true, selectors: []selector{sel}}, err
+	case p.consume("."):
+		sel, err := p.shorthand()
+		return segment{selectors: []selector{sel}}, err
+	}
+	selectors, err := p.bracketed()
+	return segment{selectors: selectors}, err
+}
+
+// shorthand parses the '*' or member name that follows '.' or "..".
+func (p *queryParser) shorthand() (selector, error) {
+	if p.consume("*") {
+		return wildcardSelector{}, nil
+	}
+	start := p.pos
+	for p.pos < len(p.text) {
+		r, size := utf8.DecodeRuneInString(p.text[p.pos:])
+		if !isNameChar(r) || (p.pos == start && r >= '0' && r <= '9') {
+			break
+		}
+		p.pos += size
+	}
+	if p.pos == start {
+		return nil, p.errorf("expected member name or '*', found %s", p.describe())
+	}
+	return nameSelector(p.text[start:p.pos]), nil
+}
+
+// isNameChar reports whether r may appear in a member name shorthand; the
+// first character must not be a digit.
+func isNameChar(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' ||
+		r >= 0x80 && r != utf8.RuneError
+}
+
+// bracketed parses a bracketed selection: '[' selectors separated by ','
+// ']'.
+func (p *queryParser) bracketed() ([]selector, error) {
+	p.pos++ // [
+	var selectors []selector
+	for {
+		p.skipBlank()
+		sel, err := p.selector()
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, sel)
+		p.skipBlank()
+		if p.consume("]") {
+			return selectors, nil
+		}
+		if !p.consume(",") {
+			return nil, p.errorf("expected ',' or ']', found %s", p.describe())
+		}
+	}
+}
+
+func (p *queryParser) selector() (selector, error) {
+	switch {
+	case p.peek('\'') || p.peek('"'):
+		name, err := p.stringLiteral()
+		return nameSelector(name), err
+	case p.consume("*"):
+		return wildcardSelector{}, nil
+	case p.consume("?"):
+		p.skipBlank()
+		expr, err := p.logicalOr()
+		return filterSelector{expr}, err
+	}
+	return p.indexOrSlice()
+}
+
+// indexOrSlice parses an index such as 3 or -1, or a slice such as 1:5:2
+// in which every part is optional.
+func (p *queryParser) indexOrSlice() (selector, error) {
+	var bounds [3]*int
+	for part := 0; part < 3; part++ {
+		if p.peek('-') || (p.pos < len(p.text) && p.text[p.pos] >= '0' && p.text[p.pos] <= '9') {
+			n, err := p.integer()
+			if err != nil {
+				return nil, err
+			}
+			bounds[part] = &n
+			p.skipBlank()
+		}
+		if part == 0 && !p.peek(':') {
+			if bounds[0] == nil {
+				return nil, p.errorf("expected selector, found %s", p.describe())
+			}
+			return indexSelector(*bounds[0]), nil
+		}
+		if part == 2 || !p.consume(":") {
+			break
+		}
+		p.skipBlank()
+	}
+	return sliceSelector{start: bounds[0], end: bounds[1], step: bounds[2]}, nil
+}
+
+// integer parses an integer without leading zeros and within ±maxInt.
+func (p *queryParser) integer() (int, error) {
+	start := p.pos
+	p.consume("-")
+	digits := p.pos
+	for p.pos < len(p.text) && p.text[p.pos] >= '0' && p.text[p.pos] <= '9' {
+		p.pos++
+	}
+	text := p.text[start:p.pos]
+	if p.pos == digits || (p.text[digits] == '0' && (p.pos-digits > 1 || digits > start)) {
+		return 0, p.errorfAt(start, "invalid integer %q", text)
+	}
+	n, err := strconv.Atoi(text)
+	if err != nil || n > maxInt || n < -maxInt {
+		return 0, p.errorfAt(start, "integer %s out of range", text)
+	}
+	return n, nil
+}
+
+// logicalOr parses a logical expression: && binds tighter than ||.
+func (p *queryParser) logicalOr() (logicalExpr, error) {
+	var or orExpr
+	for {
+		var and andExpr
+		for {
+			expr, err := p.basicExpr()
+			if err != nil {
+				return nil, err
+			}
+			and = append(and, expr)
+			if !p.operator("&&") {
+				break
+			}
+		}
+		if len(and) == 1 {
+			or = append(or, and[0])
+		} else {
+			or = append(or, and)
+		}
+		if !p.operator("||") {
+			break
+		}
+	}
+	if len(or) == 1 {
+		return or[0], nil
+	}
+	return or, nil
+}
+
+// operator consumes op and the blank space around it, if op comes next.
+func (p *queryParser) operator(op string) bool {
+	start := p.pos
+	p.skipBlank()
+	if p.consume(op) {
+		p.skipBlank()
+		return true
+	}
+	p.pos = start
+	return false
+}
+
+// basicExpr parses a parenthesized or negated expression, a comparison or a
+// test of a query or function.
+func (p *queryParser) basicExpr() (logicalExpr, error) {
+	if p.consume("!") {
+		p.skipBlank()
+		if p.peek('(') {
+			expr, err := p.parenExpr()
+			return notExpr{expr}, err
+		}
+		start := p.pos
+		operand, err := p.operand()
+		if err != nil {
+			return nil, err
+		}
+		expr, err := p.testExpr(operand, start)
+		return notExpr{expr}, err
+	}
+	if p.peek('(') {
+		return p.parenExpr()
+	}
+
+	start := p.pos
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	afterLeft := p.pos
+	p.skipBlank()
+	op := p.comparisonOperator()
+	if op == "" {
+		p.pos = afterLeft
+		return p.testExpr(left, start)
+	}
+	p.skipBlank()
+	rightStart := p.pos
+	right, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	l, err := p.comparable(left, start)
+	if err != nil {
+		return nil, err
+	}
+	r, err := p.comparable(right, rightStart)
+	if err != nil {
+		return nil, err
+	}
+	return comparisonExpr{op: op, left: l, right: r}, nil
+}
+
+func (p *queryParser) parenExpr() (logicalExpr, error) {
+	p.pos++ // (
+	p.skipBlank()
+	expr, err := p.logicalOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipBlank()
+	if !p.consume(")") {
+		return nil, p.errorf("expected ')', found %s", p.describe())
+	}
+	return expr, nil
+}
+
+func (p *queryParser) comparisonOperator() string {
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.consume(op) {
+			return op
+		}
+	}
+	return ""
+}
+
+// operand parses a literal, a query or a function call: anything that may
+// be compared or tested. Whether it may be used where it appears is checked
+// by comparable and testExpr.
+func (p *queryParser) operand() (any, error) {
+	switch {
+	case p.peek('$') || p.peek('@'):
+		q := &filterQuery{relative: p.text[p.pos] == '@'}
+		p.pos++
+		var err error
+		q.segments, err = p.segments()
+		return q, err
+	case p.peek('\'') || p.peek('"'):
+		s, err := p.stringLiteral()
+		return literal{s}, err
+	case p.peek('-') || (p.pos < len(p.text) && p.text[p.pos] >= '0' && p.text[p.pos] <= '9'):
+		return p.numberLiteral()
+	}
+
+	start := p.pos
+	for p.pos < len(p.text) && (p.text[p.pos] >= 'a' && p.text[p.pos] <= 'z' ||
+		p.pos > start && (p.text[p.pos] == '_' || p.text[p.pos] >= '0' && p.text[p.pos] <= '9')) {
+		p.pos++
+	}
+	name := p.text[start:p.pos]
+	if p.peek('(') {
+		return p.functionCall(name, start)
+	}
+	switch name {
+	case "true":
+		return literal{true}, nil
+	case "false":
+		return literal{false}, nil
+	case "null":
+		return literal{nil}, nil
+	}
+	p.pos = start
+	return nil, p.errorf("expected a value, query or function, found %s", p.describe())
+}
+
+// comparable checks that operand, which starts at start, has a single value
+// to compare: a literal, a singular query or a function returning a value.
+func (p *queryParser) comparable(operand any, start int) (comparable, error) {
+	switch o := operand.(type) {
+	case literal:
+		return o, nil
+	case *filterQuery:
+		if !o.singular() {
+			return nil, p.errorfAt(start, "only a query that selects at most one node can be compared")
+		}
+		return singularQuery{o}, nil
+	case *functionExpr:
+		if o.fn.result != valueType {
+			return nil, p.errorfAt(start, "the result of %s() cannot be compared", o.name)
+		}
+		return o, nil
+	}
+	panic("unreachable")
+}
+
+// testExpr checks that operand, which starts at start, can be tested on its
+// own: a query, which tests whether it selects anything, or a function
+// returning a logical value or nodes.
+func (p *queryParser) testExpr(operand any, start int) (logicalExpr, error) {
+	switch o := operand.(type) {
+	case *filterQuery:
+		return existsExpr{o}, nil
+	case *functionExpr:
+		if o.fn.result == valueType {
+			return nil, p.errorfAt(start, "the result of %s() must be compared", o.name)
+		}
+		return functionTest{o}, nil
+	}
+	return nil, p.errorfAt(start, "a literal must be compared")
+}
+
+// functionCall parses the arguments of a call to the function name, which
+// starts at start, and checks them against its parameters.
+func (p *queryParser) functionCall(name string, start int) (*functionExpr, error) {
+	fn, ok := functions[name]
+	if !ok {
+		return nil, p.errorfAt(start, "unknown function %s()", name)
+	}
+	p.pos++ // (
+	call := &functionExpr{name: name, fn: fn}
+	for i := 0; ; i++ {
+		p.skipBlank()
+		if i == 0 && p.consume(")") {
+			break
+		}
+		if i == len(fn.params) {
+			return nil, p.errorf("%s() takes %d arguments", name, len(fn.params))
+		}
+		arg, err := p.argument(call, fn.params[i])
+		if err != nil {
+			return nil, err
+		}
+		call.args = append(call.args, arg)
+		p.skipBlank()
+		if p.consume(")") {
+			break
+		}
+		if !p.consume(",") {
+			return nil, p.errorf("expected ',' or ')', found %s", p.describe())
+		}
+	}
+	if len(call.args) != len(fn.params) {
+		return nil, p.errorfAt(start, "%s() takes %d arguments", name, len(fn.params))
+	}
+	if fn.prepare != nil {
+		fn.prepare(call)
+	}
+	return call, nil
+}
+
+// argument parses an argument of call for a parameter of type typ.
+func (p *queryParser) argument(call *functionExpr, typ funcType) (any, error) {
+	start := p.pos
+	if typ == logicalType {
+		return p.logicalOr()
+	}
+	operand, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	if end := p.pos; p.operator("==") || p.operator("!=") || p.operator("<") || p.operator(">") ||
+		p.operator("&&") || p.operator("||") {
+		p.pos = end
+		return nil, p.errorfAt(start, "a logical expression is not a valid argument of %s()", call.name)
+	}
+	if typ == valueType {
+		if q, ok := operand.(*filterQuery); ok && !q.singular() {
+			return nil, p.errorfAt(start, "argument of %s() must be a value or a query that selects at most one node", call.name)
+		}
+		return p.comparable(operand, start)
+	}
+	switch o := operand.(type) {
+	case *filterQuery:
+		return o, nil
+	case *functionExpr:
+		if o.fn.result == nodesType {
+			return o, nil
+		}
+	}
+	return nil, p.errorfAt(start, "argument of %s() must be a query", call.name)
+}
+
+// stringLiteral parses a string in single or double quotes with the
+// escapes of JSON, plus \' in single quotes.
+func (p *queryParser) stringLiteral() (string, error) {
+	quote := p.text[p.pos]
+	p.pos++
+	var sb strings.Builder
+	for {
+		if p.pos >= len(p.text) {
+			return "", p.errorf("unterminated string")
+		}
+		c := p.text[p.pos]
+		switch {
+		case c == quote:
+			p.pos++
+			return sb.String(), nil
+		case c < 0x20:
+			return "", p.errorf("control character %#x in string", c)
+		case c == '\\':
+			r, err := p.escape(quote)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteRune(r)
+		default:
+			r, size := utf8.DecodeRuneInString(p.text[p.pos:])
+			if r == utf8.RuneError && size == 1 {
+				return "", p.errorf("invalid UTF-8 in string")
+			}
+			sb.WriteString(p.text[p.pos : p.pos+size])
+			p.pos += size
+		}
+	}
+}
+
+// escape parses the escape sequence at p.pos in a string quoted by quote.
+func (p *queryParser) escape(quote byte) (rune, error) {
+	start := p.pos
+	p.pos += 2
+	if start+1 >= len(p.text) {
+		return 0, p.errorfAt(start, "unterminated string")
+	}
+	switch c := p.text[start+1]; c {
+	case 'b':
+		return '\b', nil
+	case 'f':
+		return '\f', nil
+	case 'n':
+		return '\n', nil
+	case 'r':
+		return '\r', nil
+	case 't':
+		return '\t', nil
+	case '/', '\\':
+		return rune(c), nil
+	case 'u':
+		r, ok := p.hex4()
+		if !ok {
+			return 0, p.errorfAt(start, "invalid \\u escape")
+		}
+		if utf16.IsSurrogate(r) {
+			if r >= 0xDC00 || !p.consume(`\u`) {
+				return 0, p.errorfAt(start, "unpaired surrogate in \\u escape")
+			}
+			low, ok := p.hex4()
+			if r = utf16.DecodeRune(r, low); !ok || r == utf8.RuneError {
+				return 0, p.errorfAt(start, "unpaired surrogate in \\u escape")
+			}
+		}
+		return r, nil
+	case quote:
+		return rune(c), nil
+	}
+	return 0, p.errorfAt(start, "invalid escape %q", p.text[start:p.pos])
+}
+
+func (p *queryParser) hex4() (rune, bool) {
+	if p.pos+4 > len(p.text) {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(p.text[p.pos:p.pos+4], 16, 32)
+	if err != nil {
+		return 0, false
+	}
+	p.pos += 4
+	return rune(n), true
+}
+
+// numberLiteral parses a number as JSON writes it, except that -0 may be
+// followed by a fraction or exponent like any other integer part.
+func (p *queryParser) numberLiteral() (literal, error) {
+	start := p.pos
+	p.consume("-")
+	digits := p.pos
+	p.digits()
+	if p.pos == digits || (p.text[digits] == '0' && p.pos-digits > 1) {
+		return literal{}, p.errorfAt(start, "invalid number %q", p.text[start:p.pos])
+	}
+	if p.consume(".") && !p.digits() {
+		return literal{}, p.errorfAt(start, "invalid number %q", p.text[start:p.pos])
+	}
+	if p.consume("e") || p.consume("E") {
+		if !p.consume("+") {
+			p.consume("-")
+		}
+		if !p.digits() {
+			return literal{}, p.errorfAt(start, "invalid number %q", p.text[start:p.pos])
+		}
+	}
+	f, err := strconv.ParseFloat(p.text[start:p.pos], 64)
+	if err != nil {
+		return literal{}, p.errorfAt(start, "number %s out of range", p.text[start:p.pos])
+	}
+	return literal{f}, nil
+}
+
+// digits consumes a run of decimal digits and reports whether there was one.
+func (p *queryParser) digits() bool {
+	start := p.pos
+	for p.pos < len(p.text) && p.text[p.pos] >= '0' && p.text[p.pos] <= '9' {
+		p.pos++
+	}
+	return p.pos > start
+}
+
+func (p *queryParser) skipBlank() {
+	for p.pos < len(p.text) {
+		switch p.text[p.pos] {
+		case ' ', '\t', '\n', '\r':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (p *queryParser) peek(c byte) bool {
+	return p.pos < len(p.text) && p.text[p.pos] == c
+}
+
+func (p *queryParser) consume(s string) bool {
+	if strings.HasPrefix(p.text[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+// describe describes what is at p.pos for error messages.
+func (p *queryParser) describe() string {
+	if p.pos >= len(p.text) {
+		return "end of query"
+	}
+	r, _ := utf8.DecodeRuneInString(p.text[p.pos:])
+	return strconv.QuoteRune(r)
+}
+
+func (p *queryParser) errorf(format string, args ...any) error {
+	return p.errorfAt(p.pos, format, args...)
+}
+
+func (p *queryParser) errorfAt(offset int, format string, args ...any) error {
+	return scanner.SyntaxError{Msg: fmt.Sprintf(format, args...), Location: scanner.New(p.text).Locate(offset)}
+}
@@ -0,0 +1,13 @@
+package mergepatch
+
+import "fmt"
+
+// NullValueError reports a member of the modified document that is null
+// where the original is not, which a merge patch cannot express.
+type NullValueError struct {
+	Pointer string // the JSON pointer to the member
+}
+
+func (e *NullValueError) Error() string {
+	return fmt.Sprintf("merge patch cannot set %q to null", e.Pointer)
+}
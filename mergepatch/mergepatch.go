@@ -0,0 +1,164 @@
+// Package mergepatch implements JSON Merge Patch (RFC 7396), where a patch
+// looks like the document it changes: its members replace those of the
+// target, objects merge recursively and null deletes a member.
+package mergepatch
+
+import (
+	"github.com/Ronit-Raj/json-parser/parser"
+	"github.com/Ronit-Raj/json-parser/patch"
+	"github.com/Ronit-Raj/json-parser/pointer"
+)
+
+// Apply returns the result of applying the merge patch p to target, both
+// values as parser.Decode builds them into an interface value. If p is not
+// an object, it replaces target entirely.
+//
+// Neither target nor p is modified, but the result may share arrays and
+// unchanged members with them. Objects that are *parser.OrderedObject keep
+// their order, with new members added in the order of the patch.
+func Apply(target, p any) any {
+	if !isObject(p) {
+		return p
+	}
+	var result any
+	switch t := target.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(t))
+		for key, value := range t {
+			m[key] = value
+		}
+		result = m
+	case *parser.OrderedObject:
+		o := &parser.OrderedObject{}
+		for _, member := range t.Members() {
+			o.Set(member.Key, member.Value)
+		}
+		result = o
+	default:
+		// Anything but an object is replaced by an empty object of the
+		// patch's kind.
+		if _, ok := p.(*parser.OrderedObject); ok {
+			result = &parser.OrderedObject{}
+		} else {
+			result = map[string]any{}
+		}
+	}
+
+	for _, member := range members(p) {
+		if member.Value == nil {
+			deleteMember(result, member.Key)
+		} else {
+			old, _ := get(result, member.Key)
+			setMember(result, member.Key, Apply(old, member.Value))
+		}
+	}
+	return result
+}
+
+// Create returns a merge patch that turns original into modified, so that
+// Apply(original, Create(original, modified)) is equal to modified. Members
+// that are equal in both are left out, objects in both are diffed
+// recursively and everything else, including arrays, is replaced whole.
+//
+// A merge patch cannot set a member to null, since null deletes it instead,
+// so if modified has a null member that original lacks or has with another
+// value, Create returns a *NullValueError.
+func Create(original, modified any) (any, error) {
+	return create(pointer.Pointer{}, original, modified)
+}
+
+func create(path pointer.Pointer, original, modified any) (any, error) {
+	if !isObject(modified) {
+		return modified, nil
+	}
+	if !isObject(original) {
+		// Apply replaces original with an empty object first.
+		original = map[string]any{}
+	}
+	result := &parser.OrderedObject{}
+	for _, member := range members(original) {
+		if _, ok := get(modified, member.Key); !ok {
+			result.Set(member.Key, nil)
+		}
+	}
+	for _, member := range members(modified) {
+		old, ok := get(original, member.Key)
+		if ok && patch.Equal(old, member.Value) {
+			continue
+		}
+		child := append(path[:len(path):len(path)], member.Key)
+		if member.Value == nil {
+			return nil, &NullValueError{Pointer: child.String()}
+		}
+		value, err := create(child, old, member.Value)
+		if err != nil {
+			return nil, err
+		}
+		result.Set(member.Key, value)
+	}
+	if _, ok := original.(*parser.OrderedObject); ok {
+		return result, nil
+	}
+	if _, ok := modified.(*parser.OrderedObject); ok {
+		return result, nil
+	}
+	// Neither side keeps its order, so neither does the patch.
+	m := make(map[string]any, result.Len())
+	for _, member := range result.Members() {
+		m[member.Key] = member.Value
+	}
+	return m, nil
+}
+
+func isObject(v any) bool {
+	switch v.(type) {
+	case map[string]any, *parser.OrderedObject:
+		return true
+	}
+	return false
+}
+
+// members returns the members of the object v, in order for a
+// *parser.OrderedObject.
+func members(v any) []parser.Member {
+	switch v := v.(type) {
+	case map[string]any:
+		members := make([]parser.Member, 0, len(v))
+		for key, value := range v {
+			members = append(members, parser.Member{Key: key, Value: value})
+		}
+		return members
+	case *parser.OrderedObject:
+		return v.Members()
+	}
+	return nil
+}
+
+func get(object any, key string) (any, bool) {
+	switch o := object.(type) {
+	case map[string]any:
+		v, ok := o[key]
+		return v, ok
+	case *parser.OrderedObject:
+		return o.Get(key)
+	}
+	return nil, false
+}
+
+func setMember(object any, key string, value any) {
+	switch o := object.(type) {
+	case map[string]any:
+		o[key] = value
+	case *parser.OrderedObject:
+		o.Set(key, value)
+	}
+}
+
+func deleteMember(object any, key string) {
+	switch o := object.(type) {
+	case map[string]any:
+		delete(o, key)
+	case *parser.OrderedObject:
+		o.Delete(key)
+	}
+}
@@ -0,0 +1,116 @@
+package mergepatch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Ronit-Raj/json-parser/parser"
+	"github.com/Ronit-Raj/json-parser/patch"
+)
+
+func decode(t *testing.T, text string) any {
+	t.Helper()
+	var doc any
+	if err := parser.Decode(text, &doc); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	return doc
+}
+
+// rfcExamples are the examples of RFC 7396 appendix A.
+var rfcExamples = []struct {
+	original, patch, result string
+}{
+	{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+	{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+	{`{"a":"b"}`, `{"a":null}`, `{}`},
+	{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+	{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+	{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+	{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+	{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+	{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+	{`{"a":"b"}`, `["c"]`, `["c"]`},
+	{`{"a":"foo"}`, `null`, `null`},
+	{`{"a":"foo"}`, `"bar"`, `"bar"`},
+	{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+	{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+	{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+}
+
+func TestApplyRFC(t *testing.T) {
+	for _, tt := range rfcExamples {
+		t.Run(tt.original+" "+tt.patch, func(t *testing.T) {
+			original := decode(t, tt.original)
+			got := Apply(original, decode(t, tt.patch))
+			if want := decode(t, tt.result); !patch.Equal(got, want) {
+				t.Errorf("Apply() = %v, want %v", got, want)
+			}
+			if want := decode(t, tt.original); !patch.Equal(original, want) {
+				t.Errorf("Apply() changed the target to %v", original)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	for _, tt := range rfcExamples {
+		t.Run(tt.original+" "+tt.result, func(t *testing.T) {
+			original, modified := decode(t, tt.original), decode(t, tt.result)
+			p, err := Create(original, modified)
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			if got := Apply(original, p); !patch.Equal(got, modified) {
+				t.Errorf("Apply(original, %v) = %v, want %v", p, got, modified)
+			}
+		})
+	}
+
+	got, err := Create(decode(t, `{"a": 1, "b": {"c": [1], "d": 2}, "e": 3}`), decode(t, `{"a": 1, "b": {"c": [1, 2]}, "f": 4}`))
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if want := decode(t, `{"b": {"c": [1, 2], "d": null}, "e": null, "f": 4}`); !patch.Equal(got, want) {
+		t.Errorf("Create() = %v, want %v", got, want)
+	}
+}
+
+func TestCreateNull(t *testing.T) {
+	tests := []struct {
+		original, modified string
+		pointer            string // of the NullValueError, if any
+	}{
+		{original: `{"a": {"b": 1}}`, modified: `{"a": {"b": null}}`, pointer: "/a/b"},
+		{original: `{}`, modified: `{"a~b": null}`, pointer: "/a~0b"},
+		{original: `[]`, modified: `{"a": {"b": null}}`, pointer: "/a/b"},
+		{original: `{"a": null}`, modified: `{"a": null, "b": 1}`},
+		{original: `{"a": 1}`, modified: `null`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.modified, func(t *testing.T) {
+			_, err := Create(decode(t, tt.original), decode(t, tt.modified))
+			if tt.pointer == "" {
+				if err != nil {
+					t.Errorf("Create() error = %v", err)
+				}
+				return
+			}
+			var nullErr *NullValueError
+			if !errors.As(err, &nullErr) || nullErr.Pointer != tt.pointer {
+				t.Errorf("Create() error = %v, want a NullValueError at %s", err, tt.pointer)
+			}
+		})
+	}
+}
+
+func TestOrderedObjects(t *testing.T) {
+	var target, p any
+	parser.DecodeWithOptions(`{"z": 1, "a": {"y": 2, "b": 3}}`, &target, parser.UseOrderedObject())
+	parser.DecodeWithOptions(`{"a": {"c": 4, "y": null}, "m": 5}`, &p, parser.UseOrderedObject())
+	got, err := parser.Encode(Apply(target, p))
+	if err != nil || got != `{"z":1,"a":{"b":3,"c":4},"m":5}` {
+		t.Errorf("Apply() = %s, %v", got, err)
+	}
+}
@@ -0,0 +1,38 @@
+package mergepatch
+
+import "github.com/Ronit-Raj/json-parser/parser"
+
+// textOptions keep the key order and every digit of the documents, so text
+// that goes through ApplyText or CreateText only changes where it must.
+var textOptions = []parser.Option{parser.UseOrderedObject(), parser.UseNumber()}
+
+// ApplyText applies the merge patch p to the document target, both given as
+// JSON text, and returns the result as JSON text. Members keep their order
+// and numbers keep their digits.
+func ApplyText(target, p string) (string, error) {
+	var t, pv any
+	if err := parser.DecodeWithOptions(target, &t, textOptions...); err != nil {
+		return "", err
+	}
+	if err := parser.DecodeWithOptions(p, &pv, textOptions...); err != nil {
+		return "", err
+	}
+	return parser.Encode(Apply(t, pv))
+}
+
+// CreateText returns, as JSON text, a merge patch that turns the document
+// original into modified, both given as JSON text. See Create.
+func CreateText(original, modified string) (string, error) {
+	var o, m any
+	if err := parser.DecodeWithOptions(original, &o, textOptions...); err != nil {
+		return "", err
+	}
+	if err := parser.DecodeWithOptions(modified, &m, textOptions...); err != nil {
+		return "", err
+	}
+	p, err := Create(o, m)
+	if err != nil {
+		return "", err
+	}
+	return parser.Encode(p)
+}
@@ -0,0 +1,62 @@
+package mergepatch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Ronit-Raj/json-parser/scanner"
+)
+
+func TestApplyText(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		patch    string
+		expected string
+	}{
+		{name: "keeps order", target: `{"title": "Goodbye!", "author": {"givenName": "John", "familyName": "Doe"}, "tags": ["example", "sample"], "content": "This will be unchanged"}`,
+			patch:    `{"title": "Hello!", "phoneNumber": "+01-123-456-7890", "author": {"familyName": null}, "tags": ["example"]}`,
+			expected: `{"title":"Hello!","author":{"givenName":"John"},"tags":["example"],"content":"This will be unchanged","phoneNumber":"+01-123-456-7890"}`},
+		{name: "keeps digits", target: `{"id": 12345678901234567890, "price": 1.10}`, patch: `{"qty": 1e2}`,
+			expected: `{"id":12345678901234567890,"price":1.10,"qty":1e2}`},
+		{name: "replace", target: `{"a": 1}`, patch: `[1]`, expected: `[1]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyText(tt.target, tt.patch)
+			if err != nil {
+				t.Fatalf("ApplyText() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("ApplyText() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCreateText(t *testing.T) {
+	got, err := CreateText(`{"b": 1, "a": {"x": 1, "y": 2}, "c": [1]}`, `{"b": 1, "a": {"x": 1, "y": 3}, "d": 2.50}`)
+	if err != nil {
+		t.Fatalf("CreateText() error = %v", err)
+	}
+	if want := `{"c":null,"a":{"y":3},"d":2.50}`; got != want {
+		t.Errorf("CreateText() = %s, want %s", got, want)
+	}
+
+	if _, err := CreateText(`{}`, `{"a": null}`); err == nil {
+		t.Errorf("CreateText() of a null member succeeded")
+	}
+}
+
+func TestTextErrors(t *testing.T) {
+	for _, args := range [][2]string{{`{"a": 1`, `{}`}, {`{}`, `{"a" 1}`}} {
+		var syntaxErr scanner.SyntaxError
+		if _, err := ApplyText(args[0], args[1]); !errors.As(err, &syntaxErr) {
+			t.Errorf("ApplyText(%s, %s) error = %v, want a SyntaxError", args[0], args[1], err)
+		}
+		if _, err := CreateText(args[0], args[1]); !errors.As(err, &syntaxErr) {
+			t.Errorf("CreateText(%s, %s) error = %v, want a SyntaxError", args[0], args[1], err)
+		}
+	}
+}
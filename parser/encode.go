@@ -0,0 +1,367 @@
+package parser
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// encodeState accumulates the output of a single call to Encode.
+type encodeState struct {
+	strings.Builder
+	// seen holds the pointers and maps currently being encoded, so that a
+	// cyclic value is reported instead of recursing forever.
+	seen map[uintptr]struct{}
+}
+
+// Encode returns the JSON encoding of v.
+//
+// Structs are encoded as objects using the same `json` tags Decode honors:
+// the tag name replaces the field name, `json:"-"` skips the field, and
+// `omitempty` skips it when it holds the zero value of its type, an empty
+// slice, map or string, or a nil pointer. Map keys are written in sorted
+// order, so the output is deterministic, while an OrderedObject keeps its
+// own order. Nil pointers, interfaces, maps and slices are encoded as null.
+//
+// Numbers and the math/big types are written with every digit they hold,
+// and a RawMessage is written as it is.
+//
+// Channels, functions, complex numbers, maps whose keys are not strings,
+// NaN and infinite floats and cyclic values cannot be encoded.
+func Encode(v any) (string, error) {
+	e := &encodeState{seen: make(map[uintptr]struct{})}
+	if err := e.value(reflect.ValueOf(v)); err != nil {
+		return "", err
+	}
+	return e.String(), nil
+}
+
+func (e *encodeState) value(v reflect.Value) error {
+	if !v.IsValid() {
+		e.WriteString("null")
+		return nil
+	}
+
+	switch v.Type() {
+	case numberType:
+		return e.number(Number(v.String()))
+	case bigIntType, bigFloatType, bigRatType:
+		return e.bigNumber(v)
+	case orderedObjectType:
+		return e.orderedObject(v.Interface().(OrderedObject))
+	case rawMessageType:
+		return e.rawMessage(v.Bytes())
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		e.WriteString(strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.WriteString(strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.WriteString(strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		return e.float(v.Float(), v.Type().Bits())
+	case reflect.String:
+		e.string(v.String())
+	case reflect.Interface:
+		if v.IsNil() {
+			e.WriteString("null")
+			return nil
+		}
+		return e.value(v.Elem())
+	case reflect.Ptr:
+		if v.IsNil() {
+			e.WriteString("null")
+			return nil
+		}
+		if err := e.enter(v); err != nil {
+			return err
+		}
+		defer delete(e.seen, v.Pointer())
+		return e.value(v.Elem())
+	case reflect.Map:
+		return e.object(v)
+	case reflect.Struct:
+		return e.structObject(v)
+	case reflect.Slice:
+		if v.IsNil() {
+			e.WriteString("null")
+			return nil
+		}
+		return e.array(v)
+	case reflect.Array:
+		return e.array(v)
+	default:
+		return fmt.Errorf("cannot encode value of type %v", v.Type())
+	}
+	return nil
+}
+
+// enter records that the pointer or map v is being encoded and fails if it
+// already is, which means the value refers back to itself.
+func (e *encodeState) enter(v reflect.Value) error {
+	ptr := v.Pointer()
+	if _, ok := e.seen[ptr]; ok {
+		return fmt.Errorf("cannot encode cyclic value of type %v", v.Type())
+	}
+	e.seen[ptr] = struct{}{}
+	return nil
+}
+
+// float writes f the way Decode reads it back: the shortest representation
+// that round-trips, switching to exponent notation for very large and very
+// small magnitudes.
+func (e *encodeState) float(f float64, bits int) error {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Errorf("cannot encode number %v", f)
+	}
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	e.WriteString(strconv.FormatFloat(f, format, -1, bits))
+	return nil
+}
+
+// number writes n verbatim. The empty Number is written as 0.
+func (e *encodeState) number(n Number) error {
+	if n == "" {
+		n = "0"
+	}
+	if err := n.validate(); err != nil {
+		return fmt.Errorf("cannot encode %w", err)
+	}
+	e.WriteString(string(n))
+	return nil
+}
+
+// rawMessage writes raw verbatim, or null if it is empty. It must hold a
+// single valid JSON value.
+func (e *encodeState) rawMessage(raw []byte) error {
+	if len(raw) == 0 {
+		e.WriteString("null")
+		return nil
+	}
+	if err := checkValid(string(raw)); err != nil {
+		return fmt.Errorf("cannot encode RawMessage: %w", err)
+	}
+	e.Write(raw)
+	return nil
+}
+
+// bigNumber writes a big.Int, big.Float or big.Rat without losing any of its
+// digits. A big.Rat is only accepted if it has a finite decimal expansion.
+func (e *encodeState) bigNumber(v reflect.Value) error {
+	if !v.CanAddr() {
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(v)
+		v = copied
+	}
+	switch n := v.Addr().Interface().(type) {
+	case *big.Int:
+		e.WriteString(n.String())
+	case *big.Float:
+		if n.IsInf() {
+			return fmt.Errorf("cannot encode number %v", n)
+		}
+		e.WriteString(n.Text('g', -1))
+	case *big.Rat:
+		places, ok := decimalPlaces(n.Denom())
+		if !ok {
+			return fmt.Errorf("cannot encode %v exactly as a decimal number", n)
+		}
+		e.WriteString(n.FloatString(places))
+	}
+	return nil
+}
+
+// decimalPlaces returns how many digits after the decimal point a fraction
+// with denominator denom needs, or false if its expansion never ends, which
+// is the case whenever denom has a prime factor other than 2 and 5.
+func decimalPlaces(denom *big.Int) (int, bool) {
+	d := new(big.Int).Set(denom)
+	twos := int(d.TrailingZeroBits())
+	d.Rsh(d, uint(twos))
+	fives := 0
+	five, rem := big.NewInt(5), new(big.Int)
+	for {
+		q, r := new(big.Int).QuoRem(d, five, rem)
+		if r.Sign() != 0 {
+			break
+		}
+		d, fives = q, fives+1
+	}
+	return max(twos, fives), d.IsInt64() && d.Int64() == 1
+}
+
+// string writes s as a quoted JSON string. Quotes, backslashes and control
+// characters are escaped, and invalid UTF-8 is replaced with U+FFFD.
+func (e *encodeState) string(s string) {
+	const hex = "0123456789abcdef"
+	e.WriteByte('"')
+	start := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+			e.WriteString(s[start:i])
+			switch c {
+			case '"', '\\':
+				e.WriteByte('\\')
+				e.WriteByte(c)
+			case '\b':
+				e.WriteString(`\b`)
+			case '\f':
+				e.WriteString(`\f`)
+			case '\n':
+				e.WriteString(`\n`)
+			case '\r':
+				e.WriteString(`\r`)
+			case '\t':
+				e.WriteString(`\t`)
+			default:
+				e.WriteString(`\u00`)
+				e.WriteByte(hex[c>>4])
+				e.WriteByte(hex[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			e.WriteString(s[start:i])
+			e.WriteString(`\ufffd`)
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	e.WriteString(s[start:])
+	e.WriteByte('"')
+}
+
+// object writes a map with its keys in sorted order.
+func (e *encodeState) object(v reflect.Value) error {
+	if v.IsNil() {
+		e.WriteString("null")
+		return nil
+	}
+	if v.Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("cannot encode map with key type %v", v.Type().Key())
+	}
+	if err := e.enter(v); err != nil {
+		return err
+	}
+	defer delete(e.seen, v.Pointer())
+
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	e.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			e.WriteByte(',')
+		}
+		e.string(key.String())
+		e.WriteByte(':')
+		if err := e.value(v.MapIndex(key)); err != nil {
+			return err
+		}
+	}
+	e.WriteByte('}')
+	return nil
+}
+
+// orderedObject writes the members of o in order.
+func (e *encodeState) orderedObject(o OrderedObject) error {
+	e.WriteByte('{')
+	for i, m := range o.members {
+		if i > 0 {
+			e.WriteByte(',')
+		}
+		e.string(m.Key)
+		e.WriteByte(':')
+		if err := e.value(reflect.ValueOf(m.Value)); err != nil {
+			return err
+		}
+	}
+	e.WriteByte('}')
+	return nil
+}
+
+// structObject writes the fields of a struct in declaration order.
+func (e *encodeState) structObject(v reflect.Value) error {
+	e.WriteByte('{')
+	first := true
+	for _, f := range cachedFields(v.Type()).list {
+		fv, ok := fieldByIndexNoAlloc(v, f.index)
+		if !ok || (f.omitEmpty && isEmptyValue(fv)) {
+			continue
+		}
+		if !first {
+			e.WriteByte(',')
+		}
+		first = false
+		e.string(f.name)
+		e.WriteByte(':')
+		if err := e.value(fv); err != nil {
+			return err
+		}
+	}
+	e.WriteByte('}')
+	return nil
+}
+
+func (e *encodeState) array(v reflect.Value) error {
+	e.WriteByte('[')
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			e.WriteByte(',')
+		}
+		if err := e.value(v.Index(i)); err != nil {
+			return err
+		}
+	}
+	e.WriteByte(']')
+	return nil
+}
+
+// fieldByIndexNoAlloc is fieldByIndex for encoding: it reports false instead
+// of allocating when an embedded pointer on the way is nil.
+func fieldByIndexNoAlloc(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// isEmptyValue reports whether v is empty for the purposes of omitempty.
+func isEmptyValue(v reflect.Value) bool {
+	if v.Type() == orderedObjectType {
+		o := v.Interface().(OrderedObject)
+		return o.Len() == 0
+	}
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	}
+	return v.IsZero()
+}
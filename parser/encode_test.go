@@ -0,0 +1,191 @@
+package parser
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	str := "pointer"
+	var nilMap map[string]any
+	var nilSlice []int
+	tests := []struct {
+		name     string
+		input    any
+		expected string
+		wantErr  bool
+	}{
+		{name: "nil", input: nil, expected: `null`},
+		{name: "true", input: true, expected: `true`},
+		{name: "false", input: false, expected: `false`},
+		{name: "int", input: -42, expected: `-42`},
+		{name: "uint64 max", input: uint64(math.MaxUint64), expected: `18446744073709551615`},
+		{name: "float", input: 3.14, expected: `3.14`},
+		{name: "float integral", input: float64(30), expected: `30`},
+		{name: "float large", input: 1e21, expected: `1e+21`},
+		{name: "float small", input: 1e-7, expected: `1e-07`},
+		{name: "float32", input: float32(0.1), expected: `0.1`},
+		{name: "string", input: "hello", expected: `"hello"`},
+		{name: "string escapes", input: "a\"b\\c\nd\te\u0001", expected: `"a\"b\\c\nd\te\u0001"`},
+		{name: "string unicode", input: "🗿 é", expected: `"🗿 é"`},
+		{name: "string invalid utf8", input: "a\xffb", expected: `"a\ufffdb"`},
+		{name: "pointer", input: &str, expected: `"pointer"`},
+		{name: "nil pointer", input: (*int)(nil), expected: `null`},
+		{name: "nil map", input: nilMap, expected: `null`},
+		{name: "nil slice", input: nilSlice, expected: `null`},
+		{name: "empty slice", input: []any{}, expected: `[]`},
+		{name: "array", input: [3]int{1, 2, 3}, expected: `[1,2,3]`},
+		{name: "mixed slice", input: []any{1.5, "x", nil, true}, expected: `[1.5,"x",null,true]`},
+		{name: "map sorted keys", input: map[string]any{"b": 1, "a": 2, "c": []any{}}, expected: `{"a":2,"b":1,"c":[]}`},
+		{
+			name:     "nested map",
+			input:    map[string]any{"user": map[string]any{"name": "Bob", "tags": []any{"x"}}},
+			expected: `{"user":{"name":"Bob","tags":["x"]}}`,
+		},
+		{
+			name: "struct with tags",
+			input: testPerson{
+				testBase: testBase{ID: "u-1"},
+				Name:     "Alice",
+				Age:      30,
+				Ignored:  "skip me",
+				Dash:     "dash",
+				Address:  testAddress{Street: "Main"},
+				Tags:     [2]string{"a", "b"},
+			},
+			expected: `{"id":"u-1","Created":"","name":"Alice","age":30,"is_admin":false,"-":"dash",` +
+				`"nickname":null,"address":{"street":"Main"},"previous":null,"friends":null,"extra":null,"tags":["a","b"]}`,
+		},
+		{
+			name: "omitempty",
+			input: struct {
+				A string         `json:"a,omitempty"`
+				B int            `json:"b,omitempty"`
+				C *int           `json:"c,omitempty"`
+				D []int          `json:"d,omitempty"`
+				E map[string]int `json:"e,omitempty"`
+				F bool           `json:"f,omitempty"`
+				G string         `json:"g"`
+			}{},
+			expected: `{"g":""}`,
+		},
+		{name: "NaN", input: math.NaN(), wantErr: true},
+		{name: "infinity", input: math.Inf(1), wantErr: true},
+		{name: "channel", input: make(chan int), wantErr: true},
+		{name: "func", input: func() {}, wantErr: true},
+		{name: "int map keys", input: map[int]string{1: "a"}, wantErr: true},
+		{name: "nested unsupported", input: map[string]any{"f": complex(1, 2)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Encode(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Encode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got != tt.expected {
+				t.Errorf("Encode() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEncodeCycle(t *testing.T) {
+	type node struct {
+		Next *node `json:"next"`
+	}
+	n := &node{}
+	n.Next = n
+	if _, err := Encode(n); err == nil {
+		t.Errorf("Encode() of a cyclic pointer succeeded")
+	}
+
+	m := map[string]any{}
+	m["self"] = m
+	if _, err := Encode(m); err == nil {
+		t.Errorf("Encode() of a cyclic map succeeded")
+	}
+
+	// The same pointer appearing twice without a cycle is fine.
+	shared := &testAddress{Street: "Main"}
+	got, err := Encode([]*testAddress{shared, shared})
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if want := `[{"street":"Main"},{"street":"Main"}]`; got != want {
+		t.Errorf("Encode() = %s, want %s", got, want)
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	inputs := []string{
+		`null`,
+		`true`,
+		`-0.000001234`,
+		`123456789012345680000`,
+		`1.7976931348623157e308`,
+		`5e-324`,
+		`"line\nbreak \"quoted\" \\ \u0000 \u001f \u2028 \ud83d\ude00"`,
+		`[]`,
+		`{}`,
+		`[1, "two", [3, [4, {}]], null, false]`,
+		`{"class": 12, "section": "A", "students": [{"name": "Alice", "marks": {"math": 95.5}, "attendance": 0.95}]}`,
+		`{"": "empty key", "a\tb": {"nested": [[], {"x": null}]}}`,
+	}
+
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			var first any
+			if err := Decode(input, &first); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			encoded, err := Encode(first)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			var second any
+			if err := Decode(encoded, &second); err != nil {
+				t.Fatalf("Decode(%s) error = %v", encoded, err)
+			}
+			if !reflect.DeepEqual(first, second) {
+				t.Errorf("round trip changed value: %#v became %#v", first, second)
+			}
+			again, err := Encode(second)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			if again != encoded {
+				t.Errorf("encoding is not stable: %s then %s", encoded, again)
+			}
+		})
+	}
+}
+
+func TestEncodeStructRoundTrip(t *testing.T) {
+	nickname := "ally"
+	in := testPerson{
+		testBase: testBase{ID: "u-1", Created: "today"},
+		Name:     "Alice",
+		Age:      30.5,
+		Admin:    true,
+		Nickname: &nickname,
+		Address:  testAddress{Street: "Main", City: "Springfield"},
+		Previous: &testAddress{Street: "Elm"},
+		Friends:  []testPerson{{Name: "Bob", Friends: []testPerson{}}},
+		Extra:    map[string]any{"k": []any{float64(1), "v"}},
+		Tags:     [2]string{"a", "b"},
+	}
+	encoded, err := Encode(in)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	var out testPerson
+	if err := Decode(encoded, &out); err != nil {
+		t.Fatalf("Decode(%s) error = %v", encoded, err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("round trip changed value:\n%+v\nbecame\n%+v", in, out)
+	}
+}
@@ -0,0 +1,165 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"unicode/utf8"
+
+	"github.com/Ronit-Raj/json-parser/scanner"
+)
+
+// Every error Decode returns because of the input embeds a scanner.Location
+// with the byte offset, line, column and a source excerpt. Malformed input is
+// a scanner.SyntaxError, and input that does not fit the value it is decoded
+// into is one of the error types below. Use errors.As to get at them.
+
+// InvalidDecodeError reports an argument to Decode that is not a non-nil
+// pointer.
+type InvalidDecodeError struct {
+	Type reflect.Type
+}
+
+func (e *InvalidDecodeError) Error() string {
+	if e.Type == nil {
+		return "non-nil pointer required, got nil"
+	}
+	return fmt.Sprintf("non-nil pointer required, got %v", e.Type)
+}
+
+// TypeError reports a JSON value that cannot be stored in the Go type it was
+// decoded into, such as a string decoded into an int.
+type TypeError struct {
+	Value string       // the kind of JSON value: "string", "number", "object", ...
+	Type  reflect.Type // the type it was decoded into
+	scanner.Location
+}
+
+func (e *TypeError) Error() string {
+	return fmt.Sprintf("%v: cannot assign %s to %v", e.Location, e.Value, e.Type)
+}
+
+// NumberError reports a JSON number that cannot be stored in the Go numeric
+// type it was decoded into, either because it is out of range or because it
+// has a fractional part and the type is an integer. With the JSON5 option it
+// also reports Infinity and NaN stored in anything but a float.
+type NumberError struct {
+	Value string       // the number as written in the input
+	Type  reflect.Type // the type it was decoded into
+	scanner.Location
+}
+
+func (e *NumberError) Error() string {
+	value, finite := canonicalNumber(e.Value)
+	if !finite {
+		return fmt.Sprintf("%v: number %s cannot be stored in %v", e.Location, e.Value, e.Type)
+	}
+	switch {
+	case e.Type.Kind() == reflect.Float32, e.Type.Kind() == reflect.Float64,
+		e.Type == bigFloatType, e.Type == bigRatType:
+		return fmt.Sprintf("%v: number %s overflows %v", e.Location, e.Value, e.Type)
+	}
+	// Only ok matters here, so there is no need to materialize the digits.
+	if _, ok := integerValue(value, 0); ok {
+		return fmt.Sprintf("%v: number %s overflows %v", e.Location, e.Value, e.Type)
+	}
+	return fmt.Sprintf("%v: number %s is not an integer and cannot be stored in %v", e.Location, e.Value, e.Type)
+}
+
+// UnknownFieldError reports an object key that matches no field of the
+// struct it was decoded into. It is only returned when unknown fields are
+// disallowed; otherwise such keys are skipped.
+type UnknownFieldError struct {
+	Key              string       // the object key as decoded
+	Type             reflect.Type // the struct type
+	scanner.Location              // where the key starts
+}
+
+func (e *UnknownFieldError) Error() string {
+	return fmt.Sprintf("%v: unknown field %q in %v", e.Location, e.Key, e.Type)
+}
+
+// DuplicateKeyError reports an object key that appears more than once in
+// the same object. It is only returned when RejectDuplicateKeys is set.
+type DuplicateKeyError struct {
+	Key              string           // the object key as decoded
+	First            scanner.Location // where the key first appears
+	scanner.Location                  // where it appears again
+}
+
+func (e *DuplicateKeyError) Error() string {
+	return fmt.Sprintf("%v: duplicate key %q, first seen at %v", e.Location, e.Key, e.First)
+}
+
+// UnmarshalerError wraps an error returned by an UnmarshalJSON or
+// UnmarshalText method with the location of the value it was given.
+type UnmarshalerError struct {
+	Type reflect.Type // the type whose method failed
+	Err  error
+	scanner.Location
+}
+
+func (e *UnmarshalerError) Error() string {
+	return fmt.Sprintf("%v: cannot decode into %v: %v", e.Location, e.Type, e.Err)
+}
+
+func (e *UnmarshalerError) Unwrap() error {
+	return e.Err
+}
+
+// syntaxError returns a SyntaxError located at the byte offset.
+func (d *decodeState) syntaxError(offset int, format string, args ...any) error {
+	return scanner.SyntaxError{Msg: fmt.Sprintf(format, args...), Location: d.scan.Locate(offset)}
+}
+
+// typeError reports that the JSON value in token cannot be stored in t.
+func (d *decodeState) typeError(token scanner.Token, t reflect.Type) error {
+	return &TypeError{Value: kindOf(token), Type: t, Location: d.scan.Locate(token.Start)}
+}
+
+// numberError reports that the number in token does not fit in t.
+func (d *decodeState) numberError(token scanner.Token, t reflect.Type) error {
+	return &NumberError{Value: token.Raw, Type: t, Location: d.scan.Locate(token.Start)}
+}
+
+// separatorError reports token, which is not what the grammar expects after
+// a key or value. err is the error peeking at token, if any; a token that
+// does not scan is described by its first character.
+func (d *decodeState) separatorError(token scanner.Token, err error, expected string) error {
+	if err == nil {
+		return d.syntaxError(token.Start, "expected %s, found %s", expected, describeToken(token))
+	}
+	token, _ = d.scan.NextToken()
+	if token.Raw == "" {
+		// The input failed before a token began, in a comment or the reader.
+		return err
+	}
+	_, size := utf8.DecodeRuneInString(token.Raw)
+	return d.syntaxError(token.Start, "expected %s, found %q", expected, token.Raw[:size])
+}
+
+// kindOf names the kind of JSON value token starts.
+func kindOf(token scanner.Token) string {
+	switch token.TypeOfToken {
+	case scanner.STRING:
+		return "string"
+	case scanner.NUMBER:
+		return "number"
+	case scanner.LITERAL_TRUE, scanner.LITERAL_FALSE:
+		return "bool"
+	case scanner.LITERAL_NULL:
+		return "null"
+	case scanner.BEGIN_ARRAY:
+		return "array"
+	case scanner.BEGIN_OBJECT:
+		return "object"
+	}
+	return "value"
+}
+
+// describeToken quotes token for an error message.
+func describeToken(token scanner.Token) string {
+	if token.TypeOfToken == scanner.EOF {
+		return "end of input"
+	}
+	return fmt.Sprintf("%q", token.Raw)
+}
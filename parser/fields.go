@@ -0,0 +1,179 @@
+package parser
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// field describes one struct field that takes part in JSON decoding.
+type field struct {
+	name      string
+	index     []int
+	typ       reflect.Type
+	tagged    bool
+	omitEmpty bool
+}
+
+// structFields is the decoded view of a struct type, built once per type.
+type structFields struct {
+	list   []field
+	byName map[string]int
+}
+
+var fieldCache sync.Map // map[reflect.Type]*structFields
+
+// cachedFields returns the JSON fields of the struct type t.
+func cachedFields(t reflect.Type) *structFields {
+	if f, ok := fieldCache.Load(t); ok {
+		return f.(*structFields)
+	}
+	f, _ := fieldCache.LoadOrStore(t, typeFields(t))
+	return f.(*structFields)
+}
+
+// lookup finds the field for an object key. An exact name match wins over a
+// case-insensitive one.
+func (sf *structFields) lookup(key string) *field {
+	if i, ok := sf.byName[key]; ok {
+		return &sf.list[i]
+	}
+	for i := range sf.list {
+		if strings.EqualFold(sf.list[i].name, key) {
+			return &sf.list[i]
+		}
+	}
+	return nil
+}
+
+// parseTag splits a `json` struct tag into its name and options.
+func parseTag(tag string) (string, string) {
+	name, opts, _ := strings.Cut(tag, ",")
+	return name, opts
+}
+
+func hasOption(opts, want string) bool {
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		if opt == want {
+			return true
+		}
+	}
+	return false
+}
+
+// typeFields walks t breadth first so that fields of embedded structs are
+// promoted, following the same visibility rules as Go field selectors: a
+// shallower field hides deeper ones, and two fields with the same name at the
+// same depth cancel each other out unless exactly one of them is tagged.
+func typeFields(t reflect.Type) *structFields {
+	type queued struct {
+		typ   reflect.Type
+		index []int
+	}
+	var found []field
+	visited := map[reflect.Type]bool{}
+	next := []queued{{typ: t}}
+
+	for len(next) > 0 {
+		current := next
+		next = nil
+		for _, q := range current {
+			if visited[q.typ] {
+				continue
+			}
+			visited[q.typ] = true
+
+			for i := 0; i < q.typ.NumField(); i++ {
+				sf := q.typ.Field(i)
+				ft := sf.Type
+				if sf.Anonymous {
+					if ft.Kind() == reflect.Ptr {
+						ft = ft.Elem()
+					}
+					if !sf.IsExported() && ft.Kind() != reflect.Struct {
+						continue
+					}
+				} else if !sf.IsExported() {
+					continue
+				}
+
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, opts := parseTag(tag)
+				index := make([]int, len(q.index)+1)
+				copy(index, q.index)
+				index[len(q.index)] = i
+
+				if name == "" && sf.Anonymous && ft.Kind() == reflect.Struct {
+					next = append(next, queued{typ: ft, index: index})
+					continue
+				}
+				tagged := name != ""
+				if !tagged {
+					name = sf.Name
+				}
+				found = append(found, field{
+					name:      name,
+					index:     index,
+					typ:       sf.Type,
+					tagged:    tagged,
+					omitEmpty: hasOption(opts, "omitempty"),
+				})
+			}
+		}
+	}
+
+	// Resolve name conflicts: sort by name, then depth, then tagged first.
+	sort.SliceStable(found, func(i, j int) bool {
+		if found[i].name != found[j].name {
+			return found[i].name < found[j].name
+		}
+		if len(found[i].index) != len(found[j].index) {
+			return len(found[i].index) < len(found[j].index)
+		}
+		return found[i].tagged && !found[j].tagged
+	})
+	var list []field
+	for i := 0; i < len(found); {
+		j := i + 1
+		for j < len(found) && found[j].name == found[i].name {
+			j++
+		}
+		dominant, ok := dominantField(found[i:j])
+		if ok {
+			list = append(list, dominant)
+		}
+		i = j
+	}
+
+	// Restore declaration order so callers see fields the way they were written.
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i].index, list[j].index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+
+	byName := make(map[string]int, len(list))
+	for i, f := range list {
+		byName[f.name] = i
+	}
+	return &structFields{list: list, byName: byName}
+}
+
+// dominantField picks the field that wins among fields sharing one name. The
+// slice is sorted by depth with tagged fields first.
+func dominantField(fields []field) (field, bool) {
+	if len(fields) > 1 && len(fields[0].index) == len(fields[1].index) && fields[0].tagged == fields[1].tagged {
+		return field{}, false
+	}
+	return fields[0], true
+}
@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/Ronit-Raj/json-parser/scanner"
+)
+
+// Indent reformats the JSON document in text so that every element of an
+// array and member of an object starts on a new line, which begins with
+// prefix followed by one copy of indent per level of nesting. Empty arrays
+// and objects stay on one line. Strings and numbers are copied as they are
+// written, and members keep their order, so only the white space changes.
+//
+// text must hold exactly one value; a malformed document gives the same
+// scanner.SyntaxError Decode would.
+func Indent(text, prefix, indent string) (string, error) {
+	if err := checkValid(text); err != nil {
+		return "", err
+	}
+	return reformat(text, prefix, indent, true), nil
+}
+
+// Compact removes all insignificant white space from the JSON document in
+// text. Like Indent, it leaves strings and numbers as they are written.
+func Compact(text string) (string, error) {
+	if err := checkValid(text); err != nil {
+		return "", err
+	}
+	return reformat(text, "", "", false), nil
+}
+
+// reformat writes the tokens of the valid document text back out, breaking
+// lines and indenting if pretty is set.
+func reformat(text, prefix, indent string, pretty bool) string {
+	var sb strings.Builder
+	sb.Grow(len(text))
+	s := scanner.New(text)
+	depth := 0
+	newline := func() {
+		if pretty {
+			sb.WriteByte('\n')
+			sb.WriteString(prefix)
+			for range depth {
+				sb.WriteString(indent)
+			}
+		}
+	}
+
+	for {
+		// text is valid, so the scanner cannot fail.
+		token, _ := s.NextToken()
+		switch token.TypeOfToken {
+		case scanner.EOF:
+			return sb.String()
+		case scanner.BEGIN_ARRAY, scanner.BEGIN_OBJECT:
+			sb.WriteString(token.Raw)
+			if next, _ := s.PeekToken(); next.TypeOfToken == scanner.END_ARRAY || next.TypeOfToken == scanner.END_OBJECT {
+				s.NextToken()
+				sb.WriteString(next.Raw)
+				continue
+			}
+			depth++
+			newline()
+		case scanner.END_ARRAY, scanner.END_OBJECT:
+			depth--
+			newline()
+			sb.WriteString(token.Raw)
+		case scanner.VALUE_SEPARATOR:
+			sb.WriteByte(',')
+			newline()
+		case scanner.NAME_SEPARATOR:
+			sb.WriteByte(':')
+			if pretty {
+				sb.WriteByte(' ')
+			}
+		default:
+			sb.WriteString(token.Raw)
+		}
+	}
+}
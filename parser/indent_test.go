@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Ronit-Raj/json-parser/scanner"
+)
+
+func TestIndent(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		prefix   string
+		indent   string
+		expected string
+	}{
+		{name: "scalar", input: ` 12.50 `, indent: "  ", expected: `12.50`},
+		{name: "empty containers", input: `{"a": [ ], "b": { }}`, indent: "  ",
+			expected: "{\n  \"a\": [],\n  \"b\": {}\n}"},
+		{name: "nested", input: `{"b":1,"a":[true,null,"x\u00e9"]}`, indent: "\t",
+			expected: "{\n\t\"b\": 1,\n\t\"a\": [\n\t\ttrue,\n\t\tnull,\n\t\t\"x\\u00e9\"\n\t]\n}"},
+		{name: "prefix", input: `[1, 2]`, prefix: "> ", indent: " ",
+			expected: "[\n>  1,\n>  2\n> ]"},
+		{name: "already indented", input: "[\n    1e3\n]", indent: "  ", expected: "[\n  1e3\n]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Indent(tt.input, tt.prefix, tt.indent)
+			if err != nil {
+				t.Fatalf("Indent() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Indent() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCompact(t *testing.T) {
+	input := "{\n  \"a b\": [ 1.0, -0 ],\r\n\t\"c\" : { \"d\" : \"x y\" } }"
+	got, err := Compact(input)
+	if err != nil {
+		t.Fatalf("Compact() error = %v", err)
+	}
+	if want := `{"a b":[1.0,-0],"c":{"d":"x y"}}`; got != want {
+		t.Errorf("Compact() = %s, want %s", got, want)
+	}
+}
+
+func TestIndentErrors(t *testing.T) {
+	for _, input := range []string{``, `[1,]`, `{"a": 1} x`, "[\n  tru\n]"} {
+		var syntaxErr scanner.SyntaxError
+		if _, err := Indent(input, "", "  "); !errors.As(err, &syntaxErr) {
+			t.Errorf("Indent(%q) error = %v, want a SyntaxError", input, err)
+		}
+		if _, err := Compact(input); !errors.As(err, &syntaxErr) {
+			t.Errorf("Compact(%q) error = %v, want a SyntaxError", input, err)
+		}
+	}
+}
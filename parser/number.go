@@ -0,0 +1,267 @@
+package parser
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/Ronit-Raj/json-parser/scanner"
+)
+
+// Number is a JSON number kept exactly as it was written in the input.
+//
+// Decoding into a Number never loses precision, and with the UseNumber
+// option numbers stored in interface values are Numbers instead of
+// float64s. Encode writes a Number back out verbatim.
+type Number string
+
+var (
+	numberType   = reflect.TypeOf(Number(""))
+	bigIntType   = reflect.TypeOf(big.Int{})
+	bigFloatType = reflect.TypeOf(big.Float{})
+	bigRatType   = reflect.TypeOf(big.Rat{})
+)
+
+// maxBigDigits bounds the decimal exponent, and so the number of digits, of
+// a number decoded into a big.Int, big.Float or big.Rat. Without it a short
+// input such as 1e999999999 would take minutes and gigabytes to expand.
+const maxBigDigits = 100000
+
+// maxIntDigits is more digits than any 64-bit integer has.
+const maxIntDigits = 40
+
+// String returns the number as written.
+func (n Number) String() string {
+	return string(n)
+}
+
+// Float64 returns the number as a float64, rounded to the nearest value.
+func (n Number) Float64() (float64, error) {
+	if err := n.validate(); err != nil {
+		return 0, err
+	}
+	f, err := strconv.ParseFloat(string(n), 64)
+	if err != nil {
+		return 0, fmt.Errorf("number %s overflows float64", n)
+	}
+	return f, nil
+}
+
+// Int64 returns the number as an int64. Numbers such as 1e3 or 2.0 that
+// have no fractional part are accepted; others are an error.
+func (n Number) Int64() (int64, error) {
+	i, err := n.integer(maxIntDigits, "int64")
+	if err != nil {
+		return 0, err
+	}
+	if !i.IsInt64() {
+		return 0, fmt.Errorf("number %s overflows int64", n)
+	}
+	return i.Int64(), nil
+}
+
+// Uint64 returns the number as a uint64, following the same rules as Int64.
+func (n Number) Uint64() (uint64, error) {
+	i, err := n.integer(maxIntDigits, "uint64")
+	if err != nil {
+		return 0, err
+	}
+	if !i.IsUint64() {
+		return 0, fmt.Errorf("number %s overflows uint64", n)
+	}
+	return i.Uint64(), nil
+}
+
+// BigInt returns the number as an exact big.Int. Like Int64 it fails if the
+// number has a fractional part.
+func (n Number) BigInt() (*big.Int, error) {
+	return n.integer(maxBigDigits, "big.Int")
+}
+
+// BigFloat returns the number as a big.Float with enough precision to hold
+// every digit of its mantissa.
+func (n Number) BigFloat() (*big.Float, error) {
+	if err := n.validate(); err != nil {
+		return nil, err
+	}
+	f, ok := parseBigFloat(string(n))
+	if !ok {
+		return nil, fmt.Errorf("number %s overflows big.Float", n)
+	}
+	return f, nil
+}
+
+// validate reports an error unless n is a single JSON number.
+func (n Number) validate() error {
+	token, err := scanner.New(string(n)).NextToken()
+	if err != nil || token.TypeOfToken != scanner.NUMBER || token.Raw != string(n) {
+		return fmt.Errorf("invalid number %q", string(n))
+	}
+	return nil
+}
+
+func (n Number) integer(maxDigits int, typ string) (*big.Int, error) {
+	if err := n.validate(); err != nil {
+		return nil, err
+	}
+	i, ok := integerValue(string(n), maxDigits)
+	if !ok {
+		return nil, fmt.Errorf("number %s is not an integer", n)
+	}
+	if i == nil {
+		return nil, fmt.Errorf("number %s overflows %s", n, typ)
+	}
+	return i, nil
+}
+
+// integerValue returns the value of the JSON number raw if it has no
+// fractional part, so that numbers such as 1e3 or 2.0 can be stored in
+// integer types. A nil result with ok set means the number is an integer
+// with more than maxDigits digits.
+func integerValue(raw string, maxDigits int) (n *big.Int, ok bool) {
+	mantissa, exp := raw, 0
+	if i := strings.IndexAny(raw, "eE"); i >= 0 {
+		mantissa = raw[:i]
+		e, err := strconv.Atoi(raw[i+1:])
+		if err != nil {
+			// The exponent does not even fit in an int.
+			return nil, !strings.HasPrefix(raw[i+1:], "-") && strings.Trim(mantissa, "-0.") != ""
+		}
+		exp = e
+	}
+	neg := strings.HasPrefix(mantissa, "-")
+	intPart, fracPart, _ := strings.Cut(strings.TrimPrefix(mantissa, "-"), ".")
+	digits := strings.TrimLeft(intPart+fracPart, "0")
+	shift := exp - len(fracPart)
+
+	if shift < 0 {
+		cut := len(digits) + shift
+		if cut < 0 {
+			cut = 0
+		}
+		if strings.Trim(digits[cut:], "0") != "" {
+			return nil, false
+		}
+		digits = digits[:cut]
+		shift = 0
+	}
+	if digits == "" {
+		return new(big.Int), true
+	}
+	if len(digits)+shift > maxDigits {
+		return nil, true
+	}
+	n, _ = new(big.Int).SetString(digits+strings.Repeat("0", shift), 10)
+	if neg {
+		n.Neg(n)
+	}
+	return n, true
+}
+
+// exponentWithin reports whether the decimal exponent of the JSON number raw
+// lies within ±limit, which bounds the work needed to convert it exactly.
+func exponentWithin(raw string, limit int) bool {
+	i := strings.IndexAny(raw, "eE")
+	if i < 0 {
+		return true
+	}
+	exp, err := strconv.Atoi(raw[i+1:])
+	return err == nil && exp >= -limit && exp <= limit
+}
+
+// parseBigFloat parses the JSON number raw with one bit of precision per
+// bit of its decimal mantissa, so that integers of any size are exact.
+func parseBigFloat(raw string) (*big.Float, bool) {
+	if !exponentWithin(raw, maxBigDigits) {
+		return nil, false
+	}
+	prec := max(64, uint(4*len(raw)))
+	f, _, err := big.ParseFloat(raw, 10, prec, big.ToNearestEven)
+	return f, err == nil
+}
+
+// storeBigNumber stores the number raw, the text of token in JSON form, into
+// v if v is a Number or one of the math/big types, and reports whether it
+// was.
+func (d *decodeState) storeBigNumber(token scanner.Token, raw string, v reflect.Value) (bool, error) {
+	switch v.Type() {
+	case numberType:
+		v.SetString(raw)
+	case bigIntType:
+		n, ok := integerValue(raw, maxBigDigits)
+		if !ok || n == nil {
+			return true, d.numberError(token, v.Type())
+		}
+		v.Set(reflect.ValueOf(n).Elem())
+	case bigFloatType:
+		f, ok := parseBigFloat(raw)
+		if !ok {
+			return true, d.numberError(token, v.Type())
+		}
+		v.Set(reflect.ValueOf(f).Elem())
+	case bigRatType:
+		if !exponentWithin(raw, maxBigDigits) {
+			return true, d.numberError(token, v.Type())
+		}
+		r, ok := new(big.Rat).SetString(raw)
+		if !ok {
+			return true, d.numberError(token, v.Type())
+		}
+		v.Set(reflect.ValueOf(r).Elem())
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// canonicalNumber rewrites the JSON5 number raw as the JSON number with the
+// same value: without a '+' sign, in decimal, and with digits on both sides
+// of a decimal point. It returns false for Infinity and NaN, which JSON
+// cannot write.
+func canonicalNumber(raw string) (string, bool) {
+	if raw == "" {
+		return raw, true
+	}
+	sign := ""
+	switch raw[0] {
+	case '+':
+		raw = raw[1:]
+	case '-':
+		sign, raw = "-", raw[1:]
+	}
+	if raw == "Infinity" || raw == "NaN" {
+		return "", false
+	}
+	if len(raw) > 2 && raw[0] == '0' && (raw[1] == 'x' || raw[1] == 'X') {
+		n, _ := new(big.Int).SetString(raw[2:], 16)
+		return sign + n.String(), true
+	}
+	mantissa, exp := raw, ""
+	if i := strings.IndexAny(raw, "eE"); i >= 0 {
+		mantissa, exp = raw[:i], raw[i:]
+	}
+	if strings.HasPrefix(mantissa, ".") {
+		mantissa = "0" + mantissa
+	}
+	return sign + strings.TrimSuffix(mantissa, ".") + exp, true
+}
+
+// storeNonFinite stores Infinity or NaN, which only floats can hold.
+func (d *decodeState) storeNonFinite(token scanner.Token, v reflect.Value) error {
+	switch {
+	case v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64:
+		v.SetFloat(token.NumVal)
+	case isEmptyInterface(v) && !d.opts.useNumber:
+		v.Set(reflect.ValueOf(token.NumVal))
+	case isEmptyInterface(v):
+		return d.numberError(token, numberType)
+	case v.Kind() >= reflect.Int && v.Kind() <= reflect.Uintptr,
+		v.Type() == numberType, v.Type() == bigIntType, v.Type() == bigFloatType, v.Type() == bigRatType:
+		return d.numberError(token, v.Type())
+	default:
+		return d.typeError(token, v.Type())
+	}
+	return nil
+}
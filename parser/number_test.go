@@ -0,0 +1,228 @@
+package parser
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNumberAccessors(t *testing.T) {
+	tests := []struct {
+		number  Number
+		int64   any // int64, or nil if Int64 should fail
+		uint64  any // uint64, or nil if Uint64 should fail
+		float64 any // float64, or nil if Float64 should fail
+		bigInt  string
+	}{
+		{number: "0", int64: int64(0), uint64: uint64(0), float64: float64(0), bigInt: "0"},
+		{number: "-42", int64: int64(-42), float64: float64(-42), bigInt: "-42"},
+		{number: "9007199254740993", int64: int64(9007199254740993), uint64: uint64(9007199254740993), float64: float64(9007199254740992), bigInt: "9007199254740993"},
+		{number: "18446744073709551615", uint64: uint64(18446744073709551615), float64: float64(18446744073709551615), bigInt: "18446744073709551615"},
+		{number: "1.25e2", int64: int64(125), uint64: uint64(125), float64: float64(125), bigInt: "125"},
+		{number: "1.5", float64: float64(1.5)},
+		{number: "123456789012345678901234567890", float64: float64(123456789012345678901234567890), bigInt: "123456789012345678901234567890"},
+		{number: "1e400", bigInt: "1" + strings.Repeat("0", 400)},
+		{number: "not a number"},
+		{number: " 1"},
+		{number: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.number), func(t *testing.T) {
+			if i, err := tt.number.Int64(); (err == nil) != (tt.int64 != nil) || (err == nil && i != tt.int64) {
+				t.Errorf("Int64() = %v, %v, want %v", i, err, tt.int64)
+			}
+			if u, err := tt.number.Uint64(); (err == nil) != (tt.uint64 != nil) || (err == nil && u != tt.uint64) {
+				t.Errorf("Uint64() = %v, %v, want %v", u, err, tt.uint64)
+			}
+			if f, err := tt.number.Float64(); (err == nil) != (tt.float64 != nil) || (err == nil && f != tt.float64) {
+				t.Errorf("Float64() = %v, %v, want %v", f, err, tt.float64)
+			}
+			b, err := tt.number.BigInt()
+			if (err == nil) != (tt.bigInt != "") || (err == nil && b.String() != tt.bigInt) {
+				t.Errorf("BigInt() = %v, %v, want %s", b, err, tt.bigInt)
+			}
+		})
+	}
+}
+
+func TestNumberBigFloat(t *testing.T) {
+	f, err := Number("123456789012345678901234567890.5").BigFloat()
+	if err != nil {
+		t.Fatalf("BigFloat() error = %v", err)
+	}
+	if got := f.Text('f', 1); got != "123456789012345678901234567890.5" {
+		t.Errorf("BigFloat() = %s", got)
+	}
+	if _, err := Number("1e999999999").BigFloat(); err == nil {
+		t.Errorf("BigFloat() of a huge exponent succeeded")
+	}
+	if _, err := Number("x").BigFloat(); err == nil {
+		t.Errorf("BigFloat() of an invalid number succeeded")
+	}
+}
+
+func TestUseNumber(t *testing.T) {
+	input := `{"id": 9007199254740993, "list": [1.10, -0, 1e400]}`
+	var v any
+	if err := DecodeWithOptions(input, &v, UseNumber()); err != nil {
+		t.Fatalf("DecodeWithOptions() error = %v", err)
+	}
+	expected := map[string]any{
+		"id":   Number("9007199254740993"),
+		"list": []any{Number("1.10"), Number("-0"), Number("1e400")},
+	}
+	if !reflect.DeepEqual(v, expected) {
+		t.Errorf("DecodeWithOptions() = %#v, want %#v", v, expected)
+	}
+
+	// Without the option numbers are still float64s.
+	if err := Decode(input, &v); err == nil {
+		t.Errorf("Decode() of 1e400 into float64 succeeded")
+	}
+	var f any
+	if err := Decode(`9007199254740993`, &f); err != nil || f != float64(9007199254740992) {
+		t.Errorf("Decode() = %v, %v, want float64", f, err)
+	}
+}
+
+func TestDecodeBigNumbers(t *testing.T) {
+	type account struct {
+		ID      Number     `json:"id"`
+		Balance *big.Int   `json:"balance"`
+		Rate    *big.Float `json:"rate"`
+		Share   *big.Rat   `json:"share"`
+		Total   big.Int    `json:"total"`
+	}
+	input := `{"id": 9007199254740993, "balance": -123456789012345678901234567890,` +
+		` "rate": 0.125, "share": 1.5e-3, "total": 2e30}`
+	var a account
+	if err := Decode(input, &a); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if a.ID != "9007199254740993" {
+		t.Errorf("ID = %s", a.ID)
+	}
+	if got := a.Balance.String(); got != "-123456789012345678901234567890" {
+		t.Errorf("Balance = %s", got)
+	}
+	if got := a.Rate.Text('g', -1); got != "0.125" {
+		t.Errorf("Rate = %s", got)
+	}
+	if got := a.Share.String(); got != "3/2000" {
+		t.Errorf("Share = %s", got)
+	}
+	if got := a.Total.String(); got != "2"+strings.Repeat("0", 30) {
+		t.Errorf("Total = %s", got)
+	}
+
+	var empty account
+	if err := Decode(`{"balance": null}`, &empty); err != nil || empty.Balance != nil {
+		t.Errorf("Decode() of null = %v, %v", empty.Balance, err)
+	}
+
+	for _, tt := range []struct {
+		input  string
+		target any
+	}{
+		{input: `1.5`, target: new(big.Int)},
+		{input: `1e999999999`, target: new(big.Int)},
+		{input: `1e999999999`, target: new(big.Float)},
+		{input: `1e-999999999`, target: new(big.Rat)},
+	} {
+		var numErr *NumberError
+		if err := Decode(tt.input, tt.target); !errors.As(err, &numErr) {
+			t.Errorf("Decode(%s) into %T error = %v, want *NumberError", tt.input, tt.target, err)
+		}
+	}
+	if err := Decode(`"1"`, new(big.Int)); err == nil {
+		t.Errorf("Decode() of a string into big.Int succeeded")
+	}
+}
+
+func TestEncodeNumbers(t *testing.T) {
+	share, _ := new(big.Rat).SetString("3/2000")
+	third := big.NewRat(1, 3)
+	tests := []struct {
+		name     string
+		input    any
+		expected string
+		wantErr  bool
+	}{
+		{name: "number", input: Number("9007199254740993"), expected: `9007199254740993`},
+		{name: "empty number", input: Number(""), expected: `0`},
+		{name: "invalid number", input: Number("12abc"), wantErr: true},
+		{name: "big int", input: new(big.Int).Lsh(big.NewInt(1), 100), expected: `1267650600228229401496703205376`},
+		{name: "big float", input: big.NewFloat(0.125), expected: `0.125`},
+		{name: "big rat", input: share, expected: `0.0015`},
+		{name: "big rat integer", input: big.NewRat(6, 3), expected: `2`},
+		{name: "big rat repeating", input: third, wantErr: true},
+		{name: "big int value in struct", input: struct{ N big.Int }{N: *big.NewInt(7)}, expected: `{"N":7}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Encode(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Encode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got != tt.expected {
+				t.Errorf("Encode() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNumberRoundTrip(t *testing.T) {
+	input := `{"a":[12345678901234567890123,1.000,-0.5e-7]}`
+	var v any
+	if err := DecodeWithOptions(input, &v, UseNumber()); err != nil {
+		t.Fatalf("DecodeWithOptions() error = %v", err)
+	}
+	got, err := Encode(v)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if got != input {
+		t.Errorf("Encode() = %s, want %s", got, input)
+	}
+}
+
+// numberArray returns a JSON array of n numbers.
+func numberArray(n int) string {
+	return "[" + strings.Repeat("12345,", n-1) + "12345]"
+}
+
+func TestDecodeNumbersLinear(t *testing.T) {
+	// decode returns the shortest of three times taken to decode input.
+	decode := func(input string) time.Duration {
+		best := time.Duration(1<<63 - 1)
+		for i := 0; i < 3; i++ {
+			var v []any
+			begin := time.Now()
+			if err := Decode(input, &v); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			best = min(best, time.Since(begin))
+		}
+		return best
+	}
+	// Eight times as many numbers take about eight times as long, not
+	// sixty-four.
+	few, many := decode(numberArray(4000)), decode(numberArray(32000))
+	if many > 24*few+10*time.Millisecond {
+		t.Errorf("decoding 32000 numbers took %v, 4000 took %v", many, few)
+	}
+}
+
+func BenchmarkDecodeNumberArray(b *testing.B) {
+	json := numberArray(10000)
+	for i := 0; i < b.N; i++ {
+		var result []any
+		_ = Decode(json, &result)
+	}
+}
@@ -0,0 +1,190 @@
+package parser
+
+import "github.com/Ronit-Raj/json-parser/scanner"
+
+// Option changes how DecodeWithOptions or a Decoder decodes. Every setting
+// that adjusts decoding is an Option, and with no options decoding follows
+// RFC 8259 strictly:
+//
+//   - arrays and objects nest at most DefaultMaxDepth levels (MaxDepth),
+//   - numbers in interface values are float64s (UseNumber),
+//   - objects in interface values are map[string]any (UseOrderedObject),
+//   - object keys that match no struct field are skipped (DisallowUnknownFields),
+//   - the last of several equal object keys wins (DuplicateKeys),
+//   - nothing but whitespace may follow the value (AllowTrailingData),
+//   - the input must be JSON rather than JSON5 (JSON5).
+type Option func(*options)
+
+// options holds the settings of one decode. Its zero value is not ready to
+// use; newOptions fills in the defaults.
+type options struct {
+	allowTrailing   bool
+	stoppedAt       *int
+	maxDepth        int
+	useNumber       bool
+	orderedObjects  bool
+	disallowUnknown bool
+	duplicateKeys   DuplicateKeyPolicy
+	json5           bool
+}
+
+// newOptions returns the default settings adjusted by opts.
+func newOptions(opts []Option) options {
+	o := options{maxDepth: DefaultMaxDepth}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.maxDepth <= 0 {
+		o.maxDepth = DefaultMaxDepth
+	}
+	return o
+}
+
+// DefaultMaxDepth is how deeply arrays and objects may nest unless MaxDepth
+// says otherwise.
+const DefaultMaxDepth = 10000
+
+// MaxDepth limits how deeply arrays and objects may nest. Each level of
+// nesting costs stack space, so the limit keeps hostile input such as a
+// million '[' characters from exhausting it. A depth of 0 or less restores
+// DefaultMaxDepth.
+func MaxDepth(depth int) Option {
+	return func(o *options) {
+		o.maxDepth = depth
+	}
+}
+
+// UseNumber makes numbers decoded into interface values Numbers, which keep
+// every digit, instead of float64s, which round integers beyond 2^53.
+func UseNumber() Option {
+	return func(o *options) {
+		o.useNumber = true
+	}
+}
+
+// UseOrderedObject makes objects decoded into interface values
+// *OrderedObject, which keeps the order of the keys, instead of
+// map[string]any.
+func UseOrderedObject() Option {
+	return func(o *options) {
+		o.orderedObjects = true
+	}
+}
+
+// DisallowUnknownFields makes an object key that matches no field of the
+// struct being decoded into an *UnknownFieldError instead of being skipped.
+// Maps and interface values still accept any key, including those nested
+// inside a struct.
+func DisallowUnknownFields() Option {
+	return func(o *options) {
+		o.disallowUnknown = true
+	}
+}
+
+// DuplicateKeyPolicy says what to do with an object key that appears more
+// than once in the same object. RFC 8259 only says names SHOULD be unique,
+// and parsers disagree on which value counts, so a proxy and a backend may
+// read different documents from the same bytes.
+type DuplicateKeyPolicy int
+
+const (
+	// LastKeyWins stores every value in turn, so the last one remains.
+	LastKeyWins DuplicateKeyPolicy = iota
+	// FirstKeyWins keeps the first value and skips later ones.
+	FirstKeyWins
+	// RejectDuplicateKeys reports a *DuplicateKeyError.
+	RejectDuplicateKeys
+)
+
+// DuplicateKeys sets the policy for repeated object keys. When decoding into
+// a struct, keys that select the same field, such as "name" and "Name", count
+// as duplicates.
+func DuplicateKeys(policy DuplicateKeyPolicy) Option {
+	return func(o *options) {
+		o.duplicateKeys = policy
+	}
+}
+
+// AllowTrailingData lets the document continue after the top-level value.
+// Decoding stops at the end of the value and, if stoppedAt is not nil, the
+// byte offset just past it is stored there. Without this option any input
+// other than whitespace after the value is a syntax error.
+//
+// A Decoder always stops after each value, so it ignores this option.
+func AllowTrailingData(stoppedAt *int) Option {
+	return func(o *options) {
+		o.allowTrailing = true
+		o.stoppedAt = stoppedAt
+	}
+}
+
+// JSON5 accepts JSON5 (https://json5.org), the relaxed syntax often used for
+// hand-edited configuration files: comments, trailing commas in arrays and
+// objects, strings in single quotes, identifiers as object keys, and
+// hexadecimal numbers, numbers with a '+' sign or a leading or trailing
+// decimal point, Infinity and NaN. See scanner.Scanner.SetJSON5 for the
+// details. Strict JSON is valid JSON5, so such input decodes as before.
+//
+// Numbers are stored as the equivalent JSON number, so 0x1F decodes into a
+// Number as "31" and .5 as "0.5". Infinity and NaN can only be decoded into
+// floats and into interface values without UseNumber; anything else gives a
+// *NumberError. An Unmarshaler or RawMessage still gets the source text as
+// it was written.
+func JSON5() Option {
+	return func(o *options) {
+		o.json5 = true
+	}
+}
+
+// setUp applies the options the scanner itself is concerned with to s.
+func (o options) setUp(s *scanner.Scanner) *scanner.Scanner {
+	s.SetJSON5(o.json5)
+	return s
+}
+
+// DecodeWithOptions is Decode with its behavior adjusted by opts.
+func DecodeWithOptions(text string, v any, opts ...Option) error {
+	o := newOptions(opts)
+	d := &decodeState{scan: o.setUp(scanner.New(text)), opts: o}
+	if err := d.unmarshal(v); err != nil {
+		return err
+	}
+
+	if d.opts.allowTrailing {
+		if d.opts.stoppedAt != nil {
+			*d.opts.stoppedAt = d.scan.Offset()
+		}
+		return nil
+	}
+	return d.expectEnd()
+}
+
+// expectEnd reports a syntax error unless only whitespace is left.
+func (d *decodeState) expectEnd() error {
+	token, err := d.scan.PeekToken()
+	if err != nil {
+		return err
+	}
+	if token.TypeOfToken != scanner.EOF {
+		return d.syntaxError(token.Start, "unexpected %s after top-level value", describeToken(token))
+	}
+	return nil
+}
+
+// objectKey returns token as an object key, with the name in StringVal, and
+// reports whether it can be one. In JSON only strings can; JSON5 also allows
+// identifiers, including those that spell a literal such as null.
+func (o options) objectKey(token scanner.Token) (scanner.Token, bool) {
+	switch token.TypeOfToken {
+	case scanner.STRING:
+		return token, true
+	case scanner.IDENTIFIER:
+		return token, o.json5
+	case scanner.LITERAL_TRUE, scanner.LITERAL_FALSE, scanner.LITERAL_NULL, scanner.NUMBER:
+		if o.json5 && (token.TypeOfToken != scanner.NUMBER || token.Raw == "Infinity" || token.Raw == "NaN") {
+			token.StringVal = token.Raw
+			return token, true
+		}
+	}
+	return token, false
+}
@@ -0,0 +1,522 @@
+package parser
+
+import (
+	"errors"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/Ronit-Raj/json-parser/scanner"
+)
+
+func TestDecodeTrailingData(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		position int
+	}{
+		{name: "garbage after object", input: `{"a":1} garbage`, position: 8},
+		{name: "second number", input: `1 2 3`, position: 2},
+		{name: "second array", input: "[1]\n[2]", position: 4},
+		{name: "stray closing bracket", input: `[1]]`, position: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v any
+			err := Decode(tt.input, &v)
+			var syntaxErr scanner.SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Decode() error = %v, want SyntaxError", err)
+			}
+			if syntaxErr.Position != tt.position {
+				t.Errorf("error at %d, want %d (%v)", syntaxErr.Position, tt.position, err)
+			}
+		})
+	}
+
+	t.Run("invalid trailing character", func(t *testing.T) {
+		var v any
+		err := Decode(`{} @`, &v)
+		var syntaxErr scanner.SyntaxError
+		if !errors.As(err, &syntaxErr) || syntaxErr.Position != 3 {
+			t.Errorf("Decode() error = %v, want SyntaxError at 3", err)
+		}
+	})
+
+	t.Run("trailing whitespace is fine", func(t *testing.T) {
+		var v any
+		if err := Decode("{\"a\": 1} \n\t\r\n", &v); err != nil {
+			t.Errorf("Decode() error = %v", err)
+		}
+	})
+}
+
+func TestAllowTrailingData(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expected  any
+		stoppedAt int
+	}{
+		{name: "garbage after object", input: `{"a":1} garbage`, expected: map[string]any{"a": float64(1)}, stoppedAt: 7},
+		{name: "several numbers", input: `1 2 3`, expected: float64(1), stoppedAt: 1},
+		{name: "nothing after value", input: ` [true] `, expected: []any{true}, stoppedAt: 7},
+		{name: "invalid trailing bytes", input: `"s"@@@`, expected: "s", stoppedAt: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v any
+			stoppedAt := -1
+			if err := DecodeWithOptions(tt.input, &v, AllowTrailingData(&stoppedAt)); err != nil {
+				t.Fatalf("DecodeWithOptions() error = %v", err)
+			}
+			if !reflect.DeepEqual(v, tt.expected) {
+				t.Errorf("DecodeWithOptions() = %v, want %v", v, tt.expected)
+			}
+			if stoppedAt != tt.stoppedAt {
+				t.Errorf("stopped at %d, want %d", stoppedAt, tt.stoppedAt)
+			}
+		})
+	}
+
+	t.Run("nil offset", func(t *testing.T) {
+		var v any
+		if err := DecodeWithOptions(`1 2`, &v, AllowTrailingData(nil)); err != nil {
+			t.Errorf("DecodeWithOptions() error = %v", err)
+		}
+	})
+
+	t.Run("errors inside the value still fail", func(t *testing.T) {
+		var v any
+		if err := DecodeWithOptions(`[1 2] 3`, &v, AllowTrailingData(nil)); err == nil {
+			t.Errorf("DecodeWithOptions() succeeded, want error")
+		}
+	})
+}
+
+func TestMaxDepth(t *testing.T) {
+	nested := func(depth int, open, close string) string {
+		return strings.Repeat(open, depth) + strings.Repeat(close, depth)
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		opts     []Option
+		wantErr  bool
+		position int
+	}{
+		{name: "default limit arrays", input: nested(DefaultMaxDepth, "[", "]")},
+		{name: "default limit exceeded", input: nested(DefaultMaxDepth+1, "[", "]"), wantErr: true, position: DefaultMaxDepth},
+		{name: "custom limit", input: nested(3, "[", "]"), opts: []Option{MaxDepth(3)}},
+		{name: "custom limit exceeded", input: nested(4, "[", "]"), opts: []Option{MaxDepth(3)}, wantErr: true, position: 3},
+		{name: "objects count too", input: `{"a": {"b": {"c": 1}}}`, opts: []Option{MaxDepth(2)}, wantErr: true, position: 12},
+		{name: "mixed nesting", input: `[{"a": [1]}]`, opts: []Option{MaxDepth(3)}},
+		{name: "siblings do not add up", input: `[[1], [2], [3], {"a": [4]}]`, opts: []Option{MaxDepth(3)}},
+		{name: "zero restores default", input: nested(100, "[", "]"), opts: []Option{MaxDepth(0)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v any
+			err := DecodeWithOptions(tt.input, &v, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeWithOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr {
+				return
+			}
+			var syntaxErr scanner.SyntaxError
+			if !errors.As(err, &syntaxErr) || syntaxErr.Position != tt.position {
+				t.Errorf("DecodeWithOptions() error = %v, want SyntaxError at %d", err, tt.position)
+			}
+			if !strings.Contains(err.Error(), "maximum nesting depth") {
+				t.Errorf("DecodeWithOptions() error = %v, want a nesting depth error", err)
+			}
+		})
+	}
+}
+
+func TestMaxDepthHostileInput(t *testing.T) {
+	inputs := map[string]string{
+		"million brackets":       strings.Repeat("[", 1000000),
+		"million braces":         strings.Repeat(`{"a":`, 1000000),
+		"alternating":            strings.Repeat(`[{"k":`, 500000),
+		"closed million":         strings.Repeat("[", 1000000) + strings.Repeat("]", 1000000),
+		"brackets then garbage":  strings.Repeat("[", 20000) + "@",
+		"deep but within limits": strings.Repeat("[", 9999) + "1" + strings.Repeat("]", 9999),
+	}
+	for name, input := range inputs {
+		t.Run(name, func(t *testing.T) {
+			var v any
+			err := Decode(input, &v)
+			if name == "deep but within limits" {
+				if err != nil {
+					t.Errorf("Decode() error = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("Decode() succeeded, want error")
+			}
+
+			var typed []testPerson
+			if err := Decode(input, &typed); err == nil {
+				t.Errorf("Decode() into struct slice succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestMaxDepthDecoder(t *testing.T) {
+	input := strings.Repeat("[", DefaultMaxDepth+1) + strings.Repeat("]", DefaultMaxDepth+1)
+	var v any
+	if err := NewDecoder(strings.NewReader(input)).Decode(&v); err == nil || !strings.Contains(err.Error(), "maximum nesting depth") {
+		t.Errorf("Decode() error = %v, want a nesting depth error", err)
+	}
+}
+
+func TestOptionDefaults(t *testing.T) {
+	if got, want := newOptions(nil), (options{maxDepth: DefaultMaxDepth}); !reflect.DeepEqual(got, want) {
+		t.Errorf("newOptions(nil) = %+v, want %+v", got, want)
+	}
+	if got := newOptions([]Option{MaxDepth(5), MaxDepth(0)}); got.maxDepth != DefaultMaxDepth {
+		t.Errorf("MaxDepth(0) left depth %d, want %d", got.maxDepth, DefaultMaxDepth)
+	}
+
+	t.Run("max depth", func(t *testing.T) {
+		var v any
+		deepest := strings.Repeat("[", DefaultMaxDepth) + strings.Repeat("]", DefaultMaxDepth)
+		if err := DecodeWithOptions(deepest, &v); err != nil {
+			t.Errorf("DecodeWithOptions() at the default depth error = %v", err)
+		}
+		if err := DecodeWithOptions("["+deepest+"]", &v); err == nil {
+			t.Errorf("DecodeWithOptions() past the default depth succeeded")
+		}
+	})
+
+	t.Run("numbers are float64", func(t *testing.T) {
+		var v any
+		if err := DecodeWithOptions(`1`, &v); err != nil || v != float64(1) {
+			t.Errorf("DecodeWithOptions() = %#v, %v, want float64(1)", v, err)
+		}
+	})
+
+	t.Run("trailing data is an error", func(t *testing.T) {
+		var v any
+		if err := DecodeWithOptions(`1 2`, &v); err == nil {
+			t.Errorf("DecodeWithOptions() with trailing data succeeded")
+		}
+	})
+}
+
+func TestDecoderOptions(t *testing.T) {
+	dec := NewDecoder(strings.NewReader(`[9007199254740993] [[1]]`), UseNumber(), MaxDepth(1))
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if want := []any{Number("9007199254740993")}; !reflect.DeepEqual(v, want) {
+		t.Errorf("Decode() = %#v, want %#v", v, want)
+	}
+	if err := dec.Decode(&v); err == nil || !strings.Contains(err.Error(), "maximum nesting depth of 1") {
+		t.Errorf("Decode() error = %v, want a nesting depth error", err)
+	}
+
+	// Options are per Decoder.
+	v = nil
+	if err := NewDecoder(strings.NewReader(`[[1]]`)).Decode(&v); err != nil {
+		t.Errorf("Decode() with default options error = %v", err)
+	}
+}
+
+func TestDisallowUnknownFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		key      string
+		position int
+		typ      reflect.Type
+	}{
+		{name: "top level", input: `{"name": "Alice", "email": "a@example.com"}`, key: "email", position: 18, typ: reflect.TypeOf(testPerson{})},
+		{name: "nested struct", input: `{"address": {"street": "Main", "zip": 1}}`, key: "zip", position: 31, typ: reflect.TypeOf(testAddress{})},
+		{name: "struct in slice", input: `{"friends": [{"nmae": "Bob"}]}`, key: "nmae", position: 14, typ: reflect.TypeOf(testPerson{})},
+		{name: "ignored field", input: `{"Ignored": "x"}`, key: "Ignored", position: 1, typ: reflect.TypeOf(testPerson{})},
+		{name: "unexported field", input: `{"private": "x"}`, key: "private", position: 1, typ: reflect.TypeOf(testPerson{})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p testPerson
+			err := DecodeWithOptions(tt.input, &p, DisallowUnknownFields())
+			var unknownErr *UnknownFieldError
+			if !errors.As(err, &unknownErr) {
+				t.Fatalf("DecodeWithOptions() error = %v, want *UnknownFieldError", err)
+			}
+			if unknownErr.Key != tt.key || unknownErr.Position != tt.position || unknownErr.Type != tt.typ {
+				t.Errorf("error = %q at %d in %v, want %q at %d in %v",
+					unknownErr.Key, unknownErr.Position, unknownErr.Type, tt.key, tt.position, tt.typ)
+			}
+			if !strings.Contains(err.Error(), `unknown field "`+tt.key+`"`) {
+				t.Errorf("Error() = %q", err.Error())
+			}
+		})
+	}
+
+	t.Run("known fields", func(t *testing.T) {
+		input := `{"id": "u-1", "Created": "now", "NAME": "Alice", "extra": {"anything": {"goes": [1]}},` +
+			` "address": {"street": "Main"}}`
+		var p testPerson
+		if err := DecodeWithOptions(input, &p, DisallowUnknownFields()); err != nil {
+			t.Errorf("DecodeWithOptions() error = %v", err)
+		}
+	})
+
+	t.Run("maps and interfaces accept any key", func(t *testing.T) {
+		var m map[string]any
+		if err := DecodeWithOptions(`{"a": {"b": 1}}`, &m, DisallowUnknownFields()); err != nil {
+			t.Errorf("DecodeWithOptions() into map error = %v", err)
+		}
+		var v any
+		if err := DecodeWithOptions(`{"a": 1}`, &v, DisallowUnknownFields()); err != nil {
+			t.Errorf("DecodeWithOptions() into any error = %v", err)
+		}
+	})
+
+	t.Run("default skips unknown keys", func(t *testing.T) {
+		var p testPerson
+		if err := Decode(`{"email": {"x": [1, 2]}, "name": "Alice"}`, &p); err != nil || p.Name != "Alice" {
+			t.Errorf("Decode() = %+v, %v", p, err)
+		}
+	})
+
+	t.Run("decoder", func(t *testing.T) {
+		dec := NewDecoder(strings.NewReader(`{"street": "Main"} {"town": "X"}`), DisallowUnknownFields())
+		var a testAddress
+		if err := dec.Decode(&a); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		var unknownErr *UnknownFieldError
+		if err := dec.Decode(&a); !errors.As(err, &unknownErr) || unknownErr.Position != 20 {
+			t.Errorf("Decode() error = %v, want *UnknownFieldError at 20", err)
+		}
+	})
+}
+
+func TestDuplicateKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		policy   DuplicateKeyPolicy
+		expected any
+	}{
+		{name: "last wins by default", input: `{"a": 1, "b": 2, "a": 3}`, policy: LastKeyWins, expected: map[string]any{"a": float64(3), "b": float64(2)}},
+		{name: "first wins", input: `{"a": 1, "b": 2, "a": 3}`, policy: FirstKeyWins, expected: map[string]any{"a": float64(1), "b": float64(2)}},
+		{name: "first wins skips nested value", input: `{"a": 1, "a": {"b": [2]}}`, policy: FirstKeyWins, expected: map[string]any{"a": float64(1)}},
+		{name: "nested objects are separate", input: `{"a": {"a": 1}, "b": {"a": 2}}`, policy: RejectDuplicateKeys, expected: map[string]any{"a": map[string]any{"a": float64(1)}, "b": map[string]any{"a": float64(2)}}},
+		{name: "escaped keys compare decoded", input: `{"a": 1, "\u0061": 2}`, policy: FirstKeyWins, expected: map[string]any{"a": float64(1)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v any
+			if err := DecodeWithOptions(tt.input, &v, DuplicateKeys(tt.policy)); err != nil {
+				t.Fatalf("DecodeWithOptions() error = %v", err)
+			}
+			if !reflect.DeepEqual(v, tt.expected) {
+				t.Errorf("DecodeWithOptions() = %v, want %v", v, tt.expected)
+			}
+		})
+	}
+
+	t.Run("default", func(t *testing.T) {
+		var v map[string]any
+		if err := Decode(`{"a": 1, "a": 2}`, &v); err != nil || v["a"] != float64(2) {
+			t.Errorf("Decode() = %v, %v, want the last value", v, err)
+		}
+	})
+
+	t.Run("struct fields", func(t *testing.T) {
+		var first testAddress
+		input := `{"street": "Main", "Street": "Elm", "zip": 1, "zip": 2}`
+		if err := DecodeWithOptions(input, &first, DuplicateKeys(FirstKeyWins)); err != nil || first.Street != "Main" {
+			t.Errorf("DecodeWithOptions() = %+v, %v, want street Main", first, err)
+		}
+		var dupErr *DuplicateKeyError
+		err := DecodeWithOptions(input, new(testAddress), DuplicateKeys(RejectDuplicateKeys))
+		if !errors.As(err, &dupErr) || dupErr.Key != "Street" || dupErr.First.Position != 1 || dupErr.Position != 19 {
+			t.Errorf("DecodeWithOptions() error = %v, want duplicate Street at 19", err)
+		}
+	})
+
+	t.Run("error has both positions", func(t *testing.T) {
+		var v any
+		err := DecodeWithOptions("{\"a\": 1,\n \"a\": 2}", &v, DuplicateKeys(RejectDuplicateKeys))
+		var dupErr *DuplicateKeyError
+		if !errors.As(err, &dupErr) {
+			t.Fatalf("DecodeWithOptions() error = %v, want *DuplicateKeyError", err)
+		}
+		if dupErr.First.Position != 1 || dupErr.Position != 10 {
+			t.Errorf("positions = %d and %d, want 1 and 10", dupErr.First.Position, dupErr.Position)
+		}
+		if want := `2:2: duplicate key "a", first seen at 1:2`; err.Error() != want {
+			t.Errorf("Error() = %q, want %q", err.Error(), want)
+		}
+	})
+}
+
+func TestJSON5(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected any
+	}{
+		{name: "comments", input: "// leading\n[1, /* inner */ 2] // trailing", expected: []any{float64(1), float64(2)}},
+		{name: "trailing commas", input: `{"a": [1, 2,], "b": {},}`, expected: map[string]any{"a": []any{float64(1), float64(2)}, "b": map[string]any{}}},
+		{name: "identifier keys", input: `{a: 1, $b_2: 2, café: 3}`, expected: map[string]any{"a": float64(1), "$b_2": float64(2), "café": float64(3)}},
+		{name: "reserved words as keys", input: `{true: 1, null: 2, Infinity: 3, NaN: 4}`,
+			expected: map[string]any{"true": float64(1), "null": float64(2), "Infinity": float64(3), "NaN": float64(4)}},
+		{name: "single quotes", input: `{'it''s': 'say "hi"'}`, expected: nil},
+		{name: "numbers", input: `[0x1F, .5, 5., +1, -0xA]`, expected: []any{float64(31), 0.5, float64(5), float64(1), float64(-10)}},
+		{name: "line continuation", input: "'a\\\nb'", expected: "ab"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v any
+			err := DecodeWithOptions(tt.input, &v, JSON5())
+			if tt.expected == nil {
+				if err == nil {
+					t.Errorf("DecodeWithOptions() = %#v, want an error", v)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeWithOptions() error = %v", err)
+			}
+			if !reflect.DeepEqual(v, tt.expected) {
+				t.Errorf("DecodeWithOptions() = %#v, want %#v", v, tt.expected)
+			}
+			if err := Decode(tt.input, &v); err == nil {
+				t.Errorf("Decode() without JSON5 accepted %q", tt.input)
+			}
+		})
+	}
+
+	t.Run("struct", func(t *testing.T) {
+		var cfg struct {
+			Name  string  `json:"name"`
+			Ports []int   `json:"ports"`
+			Ratio float64 `json:"ratio"`
+		}
+		input := "{\n  // the service\n  name: 'api',\n  ports: [0x50, 443,],\n  ratio: Infinity,\n}"
+		if err := DecodeWithOptions(input, &cfg, JSON5()); err != nil {
+			t.Fatalf("DecodeWithOptions() error = %v", err)
+		}
+		if cfg.Name != "api" || !reflect.DeepEqual(cfg.Ports, []int{80, 443}) || !math.IsInf(cfg.Ratio, 1) {
+			t.Errorf("DecodeWithOptions() = %+v", cfg)
+		}
+	})
+
+	t.Run("empty members", func(t *testing.T) {
+		for _, input := range []string{`[,]`, `[1,,]`, `{,}`, `{a: 1,,}`} {
+			var v any
+			if err := DecodeWithOptions(input, &v, JSON5()); err == nil {
+				t.Errorf("DecodeWithOptions(%q) = %#v, want an error", input, v)
+			}
+		}
+	})
+}
+
+func TestJSON5Numbers(t *testing.T) {
+	tests := []struct {
+		input    string
+		target   any
+		expected any
+		message  string // a part of the expected error
+	}{
+		{input: `0x1F`, target: new(Number), expected: Number("31")},
+		{input: `-0x10`, target: new(Number), expected: Number("-16")},
+		{input: `.5`, target: new(Number), expected: Number("0.5")},
+		{input: `-.5e1`, target: new(Number), expected: Number("-0.5e1")},
+		{input: `+1`, target: new(Number), expected: Number("1")},
+		{input: `5.`, target: new(Number), expected: Number("5")},
+		{input: `5.e2`, target: new(int), expected: 500},
+		{input: `0xFFFFFFFFFFFFFFFF`, target: new(uint64), expected: uint64(math.MaxUint64)},
+		{input: `-Infinity`, target: new(float32), expected: float32(math.Inf(-1))},
+		{input: `Infinity`, target: new(any), expected: math.Inf(1)},
+		{input: `Infinity`, target: new(int), message: "number Infinity cannot be stored in int"},
+		{input: `-Infinity`, target: new(Number), message: "number -Infinity cannot be stored in parser.Number"},
+		{input: `NaN`, target: new(string), message: "cannot assign number to string"},
+		{input: `0x100`, target: new(uint8), message: "overflows uint8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			err := DecodeWithOptions(tt.input, tt.target, JSON5())
+			if tt.message != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.message) {
+					t.Errorf("DecodeWithOptions() error = %v, want %q", err, tt.message)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeWithOptions() error = %v", err)
+			}
+			if got := reflect.ValueOf(tt.target).Elem().Interface(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("DecodeWithOptions() = %#v, want %#v", got, tt.expected)
+			}
+		})
+	}
+
+	t.Run("NaN", func(t *testing.T) {
+		var f float64
+		if err := DecodeWithOptions(`NaN`, &f, JSON5()); err != nil || !math.IsNaN(f) {
+			t.Errorf("DecodeWithOptions() = %v, %v, want NaN", f, err)
+		}
+	})
+
+	t.Run("use number", func(t *testing.T) {
+		var v any
+		if err := DecodeWithOptions(`[0x1F, .5]`, &v, JSON5(), UseNumber()); err != nil || !reflect.DeepEqual(v, []any{Number("31"), Number("0.5")}) {
+			t.Errorf("DecodeWithOptions() = %#v, %v", v, err)
+		}
+		var numErr *NumberError
+		if err := DecodeWithOptions(`[NaN]`, &v, JSON5(), UseNumber()); !errors.As(err, &numErr) || numErr.Value != "NaN" {
+			t.Errorf("DecodeWithOptions() error = %v, want *NumberError for NaN", err)
+		}
+	})
+
+	t.Run("raw message keeps the source", func(t *testing.T) {
+		var v struct{ A RawMessage }
+		if err := DecodeWithOptions(`{A: [0x1F, 'x',]}`, &v, JSON5()); err != nil || string(v.A) != `[0x1F, 'x',]` {
+			t.Errorf("DecodeWithOptions() = %q, %v", v.A, err)
+		}
+	})
+}
+
+func TestJSON5Decoder(t *testing.T) {
+	input := "// config\n{a: 1,} /* next */ [Infinity,]\n'x'"
+	dec := NewDecoder(iotest.OneByteReader(strings.NewReader(input)), JSON5())
+	var got []any
+	for {
+		var v any
+		err := dec.Decode(&v)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		got = append(got, v)
+	}
+	expected := []any{map[string]any{"a": float64(1)}, []any{math.Inf(1)}, "x"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Decode() = %#v, want %#v", got, expected)
+	}
+}
@@ -0,0 +1,91 @@
+package parser
+
+import "reflect"
+
+// OrderedObject is a JSON object that remembers the order of its keys, for
+// documents such as configuration files that are read, changed and written
+// back out. Decode fills it in the order the keys appear, and Encode writes
+// them in that order. The zero value is an empty object ready to use.
+//
+// Values in an OrderedObject are decoded as into an interface, except that
+// nested objects are *OrderedObject as well, so the order is kept at every
+// level.
+type OrderedObject struct {
+	members []Member
+	index   map[string]int // key to position in members
+}
+
+// Member is one key and its value in an OrderedObject.
+type Member struct {
+	Key   string
+	Value any
+}
+
+var orderedObjectType = reflect.TypeOf(OrderedObject{})
+
+// Len returns the number of keys in o.
+func (o *OrderedObject) Len() int {
+	if o == nil {
+		return 0
+	}
+	return len(o.members)
+}
+
+// Keys returns the keys of o in order.
+func (o *OrderedObject) Keys() []string {
+	if o == nil {
+		return nil
+	}
+	keys := make([]string, len(o.members))
+	for i, m := range o.members {
+		keys[i] = m.Key
+	}
+	return keys
+}
+
+// Members returns a copy of the keys and values of o in order.
+func (o *OrderedObject) Members() []Member {
+	if o == nil {
+		return nil
+	}
+	return append([]Member(nil), o.members...)
+}
+
+// Get returns the value stored under key and whether there is one.
+func (o *OrderedObject) Get(key string) (any, bool) {
+	if o == nil {
+		return nil, false
+	}
+	i, ok := o.index[key]
+	if !ok {
+		return nil, false
+	}
+	return o.members[i].Value, true
+}
+
+// Set stores value under key. A new key goes at the end; an existing key
+// keeps its place and only its value changes.
+func (o *OrderedObject) Set(key string, value any) {
+	if i, ok := o.index[key]; ok {
+		o.members[i].Value = value
+		return
+	}
+	if o.index == nil {
+		o.index = make(map[string]int)
+	}
+	o.index[key] = len(o.members)
+	o.members = append(o.members, Member{Key: key, Value: value})
+}
+
+// Delete removes key and its value from o, keeping the order of the rest.
+func (o *OrderedObject) Delete(key string) {
+	i, ok := o.index[key]
+	if !ok {
+		return
+	}
+	delete(o.index, key)
+	o.members = append(o.members[:i], o.members[i+1:]...)
+	for ; i < len(o.members); i++ {
+		o.index[o.members[i].Key] = i
+	}
+}
@@ -0,0 +1,162 @@
+package parser
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOrderedObject(t *testing.T) {
+	var o OrderedObject
+	if o.Len() != 0 || len(o.Keys()) != 0 {
+		t.Fatalf("zero OrderedObject is not empty")
+	}
+	o.Set("b", 1)
+	o.Set("a", 2)
+	o.Set("c", 3)
+	o.Set("b", 4)
+	if got, want := o.Keys(), []string{"b", "a", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Keys() = %v, want %v", got, want)
+	}
+	if v, ok := o.Get("b"); !ok || v != 4 {
+		t.Errorf("Get(b) = %v, %v, want 4", v, ok)
+	}
+	if _, ok := o.Get("missing"); ok {
+		t.Errorf("Get(missing) found a value")
+	}
+
+	o.Delete("b")
+	o.Delete("missing")
+	if got, want := o.Members(), []Member{{Key: "a", Value: 2}, {Key: "c", Value: 3}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Members() = %v, want %v", got, want)
+	}
+	if v, ok := o.Get("c"); !ok || v != 3 {
+		t.Errorf("Get(c) after Delete = %v, %v, want 3", v, ok)
+	}
+
+	// Members returns a copy.
+	o.Members()[0].Value = 99
+	if v, _ := o.Get("a"); v != 2 {
+		t.Errorf("changing Members() changed the object")
+	}
+
+	var nilObj *OrderedObject
+	if nilObj.Len() != 0 || nilObj.Members() != nil {
+		t.Errorf("nil OrderedObject is not empty")
+	}
+	if _, ok := nilObj.Get("a"); ok {
+		t.Errorf("Get on nil OrderedObject found a value")
+	}
+}
+
+func TestDecodeOrderedObject(t *testing.T) {
+	input := `{"zeta": 1, "alpha": {"y": true, "x": null}, "mid": [{"b": 1, "a": 2}], "alpha2": "s"}`
+	var o OrderedObject
+	if err := Decode(input, &o); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if got, want := o.Keys(), []string{"zeta", "alpha", "mid", "alpha2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Keys() = %v, want %v", got, want)
+	}
+	alpha, _ := o.Get("alpha")
+	if nested, ok := alpha.(*OrderedObject); !ok || !reflect.DeepEqual(nested.Keys(), []string{"y", "x"}) {
+		t.Errorf("nested object = %#v, want an *OrderedObject with keys y, x", alpha)
+	}
+	mid, _ := o.Get("mid")
+	if inArray, ok := mid.([]any)[0].(*OrderedObject); !ok || !reflect.DeepEqual(inArray.Keys(), []string{"b", "a"}) {
+		t.Errorf("object in array = %#v, want an *OrderedObject with keys b, a", mid)
+	}
+
+	// Only values of the OrderedObject are affected, not later decodes.
+	var plain any
+	if err := Decode(`{"a": {}}`, &plain); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if _, ok := plain.(map[string]any)["a"].(map[string]any); !ok {
+		t.Errorf("Decode() into any = %#v, want maps", plain)
+	}
+}
+
+func TestDecodeOrderedObjectTargets(t *testing.T) {
+	type config struct {
+		Name     string         `json:"name"`
+		Settings *OrderedObject `json:"settings"`
+	}
+	var c config
+	if err := Decode(`{"name": "app", "settings": {"port": 80, "host": "h"}}`, &c); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if got := c.Settings.Keys(); !reflect.DeepEqual(got, []string{"port", "host"}) {
+		t.Errorf("Keys() = %v", got)
+	}
+
+	var v any
+	if err := DecodeWithOptions(`[{"b": 1, "a": {"d": 2, "c": 3}}]`, &v, UseOrderedObject()); err != nil {
+		t.Fatalf("DecodeWithOptions() error = %v", err)
+	}
+	obj, ok := v.([]any)[0].(*OrderedObject)
+	if !ok || !reflect.DeepEqual(obj.Keys(), []string{"b", "a"}) {
+		t.Fatalf("DecodeWithOptions() = %#v, want an *OrderedObject", v)
+	}
+
+	var dupErr *DuplicateKeyError
+	err := DecodeWithOptions(`{"a": 1, "a": 2}`, new(OrderedObject), DuplicateKeys(RejectDuplicateKeys))
+	if !errors.As(err, &dupErr) {
+		t.Errorf("DecodeWithOptions() error = %v, want *DuplicateKeyError", err)
+	}
+	var first OrderedObject
+	if err := DecodeWithOptions(`{"a": 1, "b": 2, "a": 3}`, &first, DuplicateKeys(FirstKeyWins)); err != nil {
+		t.Fatalf("DecodeWithOptions() error = %v", err)
+	}
+	if v, _ := first.Get("a"); v != float64(1) {
+		t.Errorf("first wins kept %v, want 1", v)
+	}
+
+	if err := Decode(`[1]`, new(OrderedObject)); err == nil {
+		t.Errorf("Decode() of an array into OrderedObject succeeded")
+	}
+}
+
+func TestEncodeOrderedObject(t *testing.T) {
+	inputs := []string{
+		`{}`,
+		`{"zeta":1,"alpha":{"y":true,"x":null},"mid":[{"b":1,"a":2}],"alpha2":"s"}`,
+		`{"b":"second","a":"first"}`,
+	}
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			var o OrderedObject
+			if err := Decode(input, &o); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			got, err := Encode(&o)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			if got != input {
+				t.Errorf("Encode() = %s, want %s", got, input)
+			}
+		})
+	}
+
+	var o OrderedObject
+	o.Set("name", "x")
+	o.Set("tags", []string{"a"})
+	out, err := Encode(struct {
+		Obj   OrderedObject `json:"obj"`
+		Empty OrderedObject `json:"empty,omitempty"`
+	}{Obj: o})
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if want := `{"obj":{"name":"x","tags":["a"]}}`; out != want {
+		t.Errorf("Encode() = %s, want %s", out, want)
+	}
+
+	self := new(OrderedObject)
+	self.Set("self", self)
+	if _, err := Encode(self); err == nil || !strings.Contains(err.Error(), "cyclic") {
+		t.Errorf("Encode() of a cyclic OrderedObject error = %v", err)
+	}
+}
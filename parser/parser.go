@@ -1,81 +1,285 @@
 package parser
 
 import (
-    "fmt"
-    "github.com/Ronit-Raj/json-parser/scanner"
-    "reflect"
+	"reflect"
+	"strconv"
+
+	"github.com/Ronit-Raj/json-parser/scanner"
 )
 
+// decodeState holds everything a single call to Decode needs, so concurrent
+// calls never share a cursor.
+type decodeState struct {
+	scan  *scanner.Scanner
+	opts  options
+	depth int // arrays and objects currently open
+}
+
+// Decode parses the JSON document in text and stores the result in the value
+// pointed to by v. It is safe to call Decode from multiple goroutines.
+//
+// Objects decode into maps with string keys or into structs. Struct fields
+// are matched against object keys by the name in their `json` tag, or by the
+// field name when there is no tag, falling back to a case-insensitive match.
+// Fields tagged `json:"-"` and unexported fields are ignored, and keys that
+// match no field are skipped unless DisallowUnknownFields is set. Numbers
+// decode into any integer or float type, a Number, or a big.Int, big.Float
+// or big.Rat; a number that does not fit the type is reported as a
+// *NumberError. Into an interface value Decode stores map[string]any, []any,
+// float64, string, bool or nil.
+//
+// A type that implements Unmarshaler decodes itself from the source text of
+// its value, and one that implements encoding.TextUnmarshaler decodes itself
+// from the contents of a string.
+//
+// text must hold exactly one value; anything but whitespace after it is a
+// syntax error. Use DecodeWithOptions and AllowTrailingData to accept it.
 func Decode(text string, v any) error {
-	scanner.Text = text
-	scanner.ResetPointer()
+	return DecodeWithOptions(text, v)
+}
 
+func (d *decodeState) unmarshal(v any) error {
 	rv := reflect.ValueOf(v)
 	if rv.Kind() != reflect.Ptr || rv.IsNil() {
-		return fmt.Errorf("non-nil pointer required")
+		return &InvalidDecodeError{Type: reflect.TypeOf(v)}
+	}
+	return d.value(rv.Elem())
+}
+
+// indirect walks down v through pointers, allocating them as it goes, until
+// it reaches a non-pointer. If decodingNull is true it stops at the last
+// pointer so that it can be set to nil.
+func indirect(v reflect.Value, decodingNull bool) reflect.Value {
+	for v.Kind() == reflect.Ptr {
+		if decodingNull && v.CanSet() {
+			return v
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// isEmptyInterface reports whether v can hold any decoded value.
+func isEmptyInterface(v reflect.Value) bool {
+	return v.Kind() == reflect.Interface && v.NumMethod() == 0
+}
+
+// enter records that the array or object starting with token is open and
+// fails if that nests deeper than the configured limit.
+func (d *decodeState) enter(token scanner.Token) error {
+	d.depth++
+	if d.depth > d.opts.maxDepth {
+		return d.syntaxError(token.Start, "exceeded maximum nesting depth of %d", d.opts.maxDepth)
 	}
+	return nil
+}
 
-	err, val := value()
+// value decodes the next JSON value into v, which must be settable. A zero
+// v discards the value after checking its syntax.
+func (d *decodeState) value(v reflect.Value) error {
+	token, err := d.scan.PeekToken()
 	if err != nil {
 		return err
 	}
 
-	// FIX: Handle nil values - only valid for pointers and interfaces
-	if val == nil {
-		targetKind := rv.Elem().Kind()
-		if targetKind == reflect.Ptr || targetKind == reflect.Interface {
-			rv.Elem().Set(reflect.Zero(rv.Elem().Type()))
+	if !v.IsValid() {
+		switch token.TypeOfToken {
+		case scanner.NUMBER, scanner.STRING, scanner.LITERAL_FALSE, scanner.LITERAL_TRUE, scanner.LITERAL_NULL:
+			d.scan.NextToken()
+			return nil
+		}
+	} else if done, err := d.unmarshaler(token, v); done {
+		return err
+	}
+
+	switch token.TypeOfToken {
+	case scanner.NUMBER:
+		d.scan.NextToken() // consume the token
+		return d.storeNumber(token, v)
+	case scanner.STRING:
+		d.scan.NextToken()
+		return d.storeString(token, v)
+	case scanner.LITERAL_FALSE, scanner.LITERAL_TRUE:
+		d.scan.NextToken()
+		return d.storeBool(token, v)
+	case scanner.LITERAL_NULL:
+		d.scan.NextToken()
+		return d.storeNull(token, v)
+	case scanner.BEGIN_ARRAY:
+		if isEmptyInterface(v) {
+			arr := make([]any, 0)
+			if err := d.array(reflect.ValueOf(&arr).Elem()); err != nil {
+				return err
+			}
+			v.Set(reflect.ValueOf(arr))
+			return nil
+		}
+		return d.array(v)
+	case scanner.BEGIN_OBJECT:
+		if isEmptyInterface(v) && d.opts.orderedObjects {
+			obj := new(OrderedObject)
+			if err := d.member(reflect.ValueOf(obj).Elem()); err != nil {
+				return err
+			}
+			v.Set(reflect.ValueOf(obj))
+			return nil
+		}
+		if isEmptyInterface(v) {
+			obj := make(map[string]any)
+			if err := d.member(reflect.ValueOf(&obj).Elem()); err != nil {
+				return err
+			}
+			v.Set(reflect.ValueOf(obj))
 			return nil
 		}
-		return fmt.Errorf("cannot assign null to non-pointer/interface type %v", rv.Elem().Type())
+		return d.member(v)
+	default:
+		return d.syntaxError(token.Start, "unexpected %s looking for beginning of value", describeToken(token))
 	}
+}
 
-	valType := reflect.ValueOf(val).Type()
-	targetType := rv.Elem().Type()
+func (d *decodeState) storeNumber(token scanner.Token, v reflect.Value) error {
+	v = indirect(v, false)
+	raw := token.Raw
+	if d.opts.json5 {
+		var finite bool
+		if raw, finite = canonicalNumber(raw); !finite {
+			return d.storeNonFinite(token, v)
+		}
+	}
+	if ok, err := d.storeBigNumber(token, raw, v); ok {
+		return err
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := integerValue(raw, maxIntDigits)
+		if !ok || n == nil || !n.IsInt64() || v.OverflowInt(n.Int64()) {
+			return d.numberError(token, v.Type())
+		}
+		v.SetInt(n.Int64())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, ok := integerValue(raw, maxIntDigits)
+		if !ok || n == nil || !n.IsUint64() || v.OverflowUint(n.Uint64()) {
+			return d.numberError(token, v.Type())
+		}
+		v.SetUint(n.Uint64())
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return d.numberError(token, v.Type())
+		}
+		v.SetFloat(f)
+	default:
+		if !isEmptyInterface(v) {
+			return d.typeError(token, v.Type())
+		}
+		if d.opts.useNumber {
+			v.Set(reflect.ValueOf(Number(raw)))
+			return nil
+		}
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return d.numberError(token, reflect.TypeOf(f))
+		}
+		v.Set(reflect.ValueOf(f))
+	}
+	return nil
+}
 
-	if !valType.AssignableTo(targetType) {
-		return fmt.Errorf("cannot assign %v to %v", valType, targetType)
+func (d *decodeState) storeString(token scanner.Token, v reflect.Value) error {
+	v = indirect(v, false)
+	switch {
+	case v.Kind() == reflect.String:
+		v.SetString(token.StringVal)
+	case isEmptyInterface(v):
+		v.Set(reflect.ValueOf(token.StringVal))
+	default:
+		return d.typeError(token, v.Type())
 	}
+	return nil
+}
 
-	rv.Elem().Set(reflect.ValueOf(val))
+func (d *decodeState) storeBool(token scanner.Token, v reflect.Value) error {
+	b := token.TypeOfToken == scanner.LITERAL_TRUE
+	v = indirect(v, false)
+	switch {
+	case v.Kind() == reflect.Bool:
+		v.SetBool(b)
+	case isEmptyInterface(v):
+		v.Set(reflect.ValueOf(b))
+	default:
+		return d.typeError(token, v.Type())
+	}
 	return nil
 }
 
-func value() (error, any) {
-	for token, err := scanner.PeekToken(); token.TypeOfToken != scanner.EOF; token, err = scanner.PeekToken() {
-		if err != nil {
-			return err, nil
+// storeNull sets pointers, interfaces, maps and slices to nil. Other types
+// cannot hold null.
+func (d *decodeState) storeNull(token scanner.Token, v reflect.Value) error {
+	v = indirect(v, true)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	return d.typeError(token, v.Type())
+}
+
+// fieldByIndex returns the struct field reached by index, allocating any nil
+// embedded pointers on the way. A nil pointer to an unexported embedded
+// struct cannot be allocated; fieldByIndex then returns the pointer's type
+// instead of the field.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, reflect.Type) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, v.Type()
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
 		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
+
+// member decodes an object into v, which must be a map with string keys, a
+// struct, an OrderedObject, or a pointer to one of those.
+func (d *decodeState) member(v reflect.Value) error {
+	begin, _ := d.scan.NextToken() // consume '{'
+	if err := d.enter(begin); err != nil {
+		return err
+	}
+	defer func() { d.depth-- }()
+	v = indirect(v, false)
+	var fields *structFields
+	switch v.Kind() {
+	case reflect.Invalid:
+		// Discarding the object.
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return d.typeError(begin, v.Type())
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+	case reflect.Struct:
+		if v.Type() != orderedObjectType {
+			fields = cachedFields(v.Type())
+		}
+	default:
+		return d.typeError(begin, v.Type())
+	}
+	var seen map[string]int // key to offset, for the duplicate key policy
+	if d.opts.duplicateKeys != LastKeyWins {
+		seen = make(map[string]int)
+	}
 
-		switch token.TypeOfToken {
-		case scanner.NUMBER:
-			scanner.NextToken() // consume the token
-			return nil, token.NumVal
-		case scanner.STRING:
-			scanner.NextToken()
-			return nil, token.StringVal
-		case scanner.LITERAL_FALSE:
-			scanner.NextToken()
-			return nil, false
-		case scanner.LITERAL_NULL:
-			scanner.NextToken()
-			return nil, nil
-		case scanner.LITERAL_TRUE:
-			scanner.NextToken()
-			return nil, true
-		case scanner.BEGIN_ARRAY:
-			return array()
-		case scanner.BEGIN_OBJECT:
-			return member()
-		default:
-			return fmt.Errorf(`Error: Unexpected token `), nil
-		}
-	}
-	return nil, nil // this should be unreachable
-}
-func member() (error, map[string]any) {
-	scanner.NextToken() // consume '{'
-	decodedObj := make(map[string]any)
 	type state int8
 	const (
 		start state = iota
@@ -87,63 +291,196 @@ func member() (error, map[string]any) {
 	)
 	var st state
 	st = start
-	var currentKey string
-	for token, err := scanner.PeekToken(); token.TypeOfToken != scanner.EOF; token, err = scanner.PeekToken() {
+	var key scanner.Token
+	var token scanner.Token
+	var err error
+	for token, err = d.scan.PeekToken(); err != nil || token.TypeOfToken != scanner.EOF; token, err = d.scan.PeekToken() {
 		if err != nil {
-			return err, nil
+			switch st {
+			case parsedKey:
+				return d.separatorError(token, err, "':' after object key")
+			case parsedValue:
+				return d.separatorError(token, err, "',' or '}' after object value")
+			}
+			return err
 		}
 
 		switch st {
 		case start:
-			scanner.NextToken() // consume the token
+			d.scan.NextToken() // consume the token
 			if token.TypeOfToken == scanner.END_OBJECT {
 				st = end
-				return nil, decodedObj
-			} else if token.TypeOfToken == scanner.STRING {
-				currentKey = token.StringVal
+				return nil
+			} else if k, ok := d.opts.objectKey(token); ok {
+				key = k
 				st = parsedKey
 			} else {
-				return fmt.Errorf(`Error:Expected string or "}" inside object`), nil
+				return d.syntaxError(token.Start, "expected string or '}' inside object, found %s", describeToken(token))
 			}
 		case parsedKey:
-			scanner.NextToken() // consume the token
+			d.scan.NextToken() // consume the token
 			if token.TypeOfToken == scanner.NAME_SEPARATOR {
-				err, val := value()
-				if err != nil {
-					return err, nil
+				if err := d.memberValue(v, fields, key, seen); err != nil {
+					return err
 				}
-				decodedObj[currentKey] = val
 				st = parsedValue
 			} else {
-				return fmt.Errorf(`Error:Expected ":" after string `), nil
+				return d.separatorError(token, nil, "':' after object key")
 			}
 		case parsedValue:
-			scanner.NextToken() // consume the token
+			d.scan.NextToken() // consume the token
 			if token.TypeOfToken == scanner.END_OBJECT {
 				st = end
-				return nil, decodedObj
+				return nil
 			} else if token.TypeOfToken == scanner.VALUE_SEPARATOR {
 				st = parsedValSep
 			} else {
-				return fmt.Errorf(`Error:Unexpected end of object`), nil
+				return d.separatorError(token, nil, "',' or '}' after object value")
 			}
 		case parsedValSep:
-			scanner.NextToken() // consume the token
-			if token.TypeOfToken == scanner.STRING {
+			d.scan.NextToken() // consume the token
+			if k, ok := d.opts.objectKey(token); ok {
 				st = parsedKey
-				currentKey = token.StringVal
+				key = k
+			} else if token.TypeOfToken == scanner.END_OBJECT && d.opts.json5 {
+				st = end
+				return nil
 			} else {
-				return fmt.Errorf(`Error:Expected string `), nil
+				return d.syntaxError(token.Start, "expected object key after ',', found %s", describeToken(token))
 			}
 		}
 
 	}
-	return fmt.Errorf(`Error:Missing closing brace for object`), nil
+	return d.syntaxError(token.Start, "missing closing brace for object opened at %v", d.scan.Locate(begin.Start))
 }
 
-func array() (error, []any) {
-	scanner.NextToken() // consume '['
-	decodedArr := make([]any, 0)
+// memberValue decodes the value stored under the key token into the map or
+// struct v. Keys that match no struct field are parsed and discarded, or are
+// an error if unknown fields are disallowed.
+func (d *decodeState) memberValue(v reflect.Value, fields *structFields, key scanner.Token, seen map[string]int) error {
+	if skip, err := d.duplicateKey(fields, key, seen); skip || err != nil {
+		if err != nil {
+			return err
+		}
+		return d.value(reflect.Value{})
+	}
+
+	if !v.IsValid() {
+		return d.value(v)
+	}
+	if v.Type() == orderedObjectType {
+		// Objects nested in an OrderedObject keep their order too.
+		saved := d.opts.orderedObjects
+		d.opts.orderedObjects = true
+		var elem any
+		err := d.value(reflect.ValueOf(&elem).Elem())
+		d.opts.orderedObjects = saved
+		if err != nil {
+			return err
+		}
+		v.Addr().Interface().(*OrderedObject).Set(key.StringVal, elem)
+		return nil
+	}
+	if fields == nil {
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if err := d.value(elem); err != nil {
+			return err
+		}
+		v.SetMapIndex(reflect.ValueOf(key.StringVal).Convert(v.Type().Key()), elem)
+		return nil
+	}
+
+	f := fields.lookup(key.StringVal)
+	if f == nil {
+		if d.opts.disallowUnknown {
+			return &UnknownFieldError{Key: key.StringVal, Type: v.Type(), Location: d.scan.Locate(key.Start)}
+		}
+		return d.value(reflect.Value{})
+	}
+	fv, unsettable := fieldByIndex(v, f.index)
+	if unsettable != nil {
+		token, err := d.scan.PeekToken()
+		if err != nil {
+			return err
+		}
+		return d.typeError(token, unsettable)
+	}
+	return d.value(fv)
+}
+
+// duplicateKey applies the duplicate key policy to key, given the offsets of
+// the keys seen so far in the object. It reports whether the value should be
+// skipped because an earlier one wins. A nil seen means the last key wins,
+// which needs no bookkeeping.
+func (d *decodeState) duplicateKey(fields *structFields, key scanner.Token, seen map[string]int) (bool, error) {
+	if seen == nil {
+		return false, nil
+	}
+	id := key.StringVal
+	if fields != nil {
+		if f := fields.lookup(id); f != nil {
+			id = f.name
+		}
+	}
+	first, ok := seen[id]
+	if !ok {
+		seen[id] = key.Start
+		return false, nil
+	}
+	if d.opts.duplicateKeys == RejectDuplicateKeys {
+		return false, &DuplicateKeyError{Key: key.StringVal, First: d.scan.Locate(first), Location: d.scan.Locate(key.Start)}
+	}
+	return true, nil
+}
+
+// array decodes an array into v, which must be a slice, an array, or a
+// pointer to one of those. Elements beyond the length of a Go array are
+// discarded and missing ones are zeroed.
+func (d *decodeState) array(v reflect.Value) error {
+	begin, _ := d.scan.NextToken() // consume '['
+	if err := d.enter(begin); err != nil {
+		return err
+	}
+	defer func() { d.depth-- }()
+	v = indirect(v, false)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Invalid:
+	default:
+		return d.typeError(begin, v.Type())
+	}
+
+	i := 0
+	element := func() error {
+		if v.Kind() == reflect.Slice && i >= v.Len() {
+			v.Set(reflect.Append(v, reflect.Zero(v.Type().Elem())))
+		}
+		var err error
+		if v.IsValid() && i < v.Len() {
+			err = d.value(v.Index(i))
+		} else {
+			err = d.value(reflect.Value{})
+		}
+		i++
+		return err
+	}
+	finish := func() {
+		if !v.IsValid() {
+			return
+		}
+		if v.Kind() == reflect.Array {
+			for ; i < v.Len(); i++ {
+				v.Index(i).Set(reflect.Zero(v.Type().Elem()))
+			}
+			return
+		}
+		if i < v.Len() {
+			v.SetLen(i)
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeSlice(v.Type(), 0, 0))
+		}
+	}
+
 	type state int8
 	const (
 		start state = iota
@@ -153,45 +490,54 @@ func array() (error, []any) {
 	)
 	var st state
 	st = start
-	for token, err := scanner.PeekToken(); token.TypeOfToken != scanner.EOF; token, err = scanner.PeekToken() {
+	var token scanner.Token
+	var err error
+	for token, err = d.scan.PeekToken(); err != nil || token.TypeOfToken != scanner.EOF; token, err = d.scan.PeekToken() {
 		if err != nil {
-			return err, nil
+			if st == parsedVal {
+				return d.separatorError(token, err, "',' or ']' after array element")
+			}
+			return err
 		}
 
 		switch st {
 		case start:
 
 			if token.TypeOfToken == scanner.END_ARRAY {
-				scanner.NextToken()
+				d.scan.NextToken()
 				st = end
-				return nil, decodedArr
+				finish()
+				return nil
 			} else {
-				err, val := value()
-				if err != nil {
-					return err, nil
+				if err := element(); err != nil {
+					return err
 				}
-				decodedArr = append(decodedArr, val)
 				st = parsedVal
 			}
 		case parsedVal:
 			if token.TypeOfToken == scanner.END_ARRAY {
-				scanner.NextToken()
+				d.scan.NextToken()
 				st = end
-				return err, decodedArr
+				finish()
+				return nil
 			} else if token.TypeOfToken == scanner.VALUE_SEPARATOR {
-				scanner.NextToken()
+				d.scan.NextToken()
 				st = parsedValSep
 			} else {
-				return fmt.Errorf("Error: Expected ',' or end of array ") , nil
+				return d.separatorError(token, nil, "',' or ']' after array element")
 			}
 		case parsedValSep:
-			err, val := value()
-			if err != nil {
-				return err, nil
+			if token.TypeOfToken == scanner.END_ARRAY && d.opts.json5 {
+				d.scan.NextToken()
+				st = end
+				finish()
+				return nil
+			}
+			if err := element(); err != nil {
+				return err
 			}
-			decodedArr = append(decodedArr, val)
 			st = parsedVal
 		}
 	}
-	return fmt.Errorf("Error:Missing closing bracket for array ") , nil
+	return d.syntaxError(token.Start, "missing closing bracket for array opened at %v", d.scan.Locate(begin.Start))
 }
@@ -1,7 +1,10 @@
 package parser
 
 import (
+	"errors"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/Ronit-Raj/json-parser/scanner"
@@ -38,6 +41,17 @@ func TestDecodeString(t *testing.T) {
 			expected: "",
 			wantErr:  false,
 		},
+		{
+			name:     "String with escapes",
+			input:    `"tab\there \"quoted\" caf\u00e9"`,
+			expected: "tab\there \"quoted\" café",
+			wantErr:  false,
+		},
+		{
+			name:    "String with invalid escape",
+			input:   `"bad \q"`,
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -477,6 +491,24 @@ func TestDecodeErrors(t *testing.T) {
 			target:  new(map[string]any),
 			wantErr: true,
 		},
+		{
+			name:    "Trailing garbage after object",
+			input:   `{"a": 1} garbage`,
+			target:  new(map[string]any),
+			wantErr: true,
+		},
+		{
+			name:    "Several top-level values",
+			input:   `1 2 3`,
+			target:  new(any),
+			wantErr: true,
+		},
+		{
+			name:    "Second object",
+			input:   `{}{}`,
+			target:  new(map[string]any),
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -587,6 +619,472 @@ func TestDecodeEmptyStructures(t *testing.T) {
 
 }
 
+func TestDecodeConcurrent(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected map[string]any
+	}{
+		{
+			input:    `{"name": "Alice", "tags": ["a", "b"]}`,
+			expected: map[string]any{"name": "Alice", "tags": []any{"a", "b"}},
+		},
+		{
+			input:    `{"nested": {"n": 1, "ok": true}}`,
+			expected: map[string]any{"nested": map[string]any{"n": float64(1), "ok": true}},
+		},
+		{
+			input:    `{"list": [[], {}, null]}`,
+			expected: map[string]any{"list": []any{[]any{}, map[string]any{}, nil}},
+		},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 64; i++ {
+		tt := tests[i%len(tests)]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				var result map[string]any
+				if err := Decode(tt.input, &result); err != nil {
+					t.Errorf("Decode(%q) error = %v", tt.input, err)
+					return
+				}
+				if !reflect.DeepEqual(result, tt.expected) {
+					t.Errorf("Decode(%q) = %v, want %v", tt.input, result, tt.expected)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+type testAddress struct {
+	Street string `json:"street"`
+	City   string `json:"city,omitempty"`
+}
+
+type testBase struct {
+	ID      string `json:"id"`
+	Created string
+}
+
+type testPerson struct {
+	testBase
+	Name     string         `json:"name"`
+	Age      float64        `json:"age"`
+	Admin    bool           `json:"is_admin"`
+	Ignored  string         `json:"-"`
+	Dash     string         `json:"-,"`
+	Nickname *string        `json:"nickname"`
+	Address  testAddress    `json:"address"`
+	Previous *testAddress   `json:"previous"`
+	Friends  []testPerson   `json:"friends"`
+	Extra    map[string]any `json:"extra"`
+	Tags     [2]string      `json:"tags"`
+	private  string
+}
+
+func TestDecodeStruct(t *testing.T) {
+	nickname := "ally"
+	tests := []struct {
+		name     string
+		input    string
+		expected testPerson
+		wantErr  bool
+	}{
+		{
+			name:  "Tagged fields",
+			input: `{"name": "Alice", "age": 30, "is_admin": true}`,
+			expected: testPerson{
+				Name:  "Alice",
+				Age:   30,
+				Admin: true,
+			},
+		},
+		{
+			name:  "Case-insensitive fallback",
+			input: `{"NAME": "Alice", "Is_Admin": true, "created": "today"}`,
+			expected: testPerson{
+				testBase: testBase{Created: "today"},
+				Name:     "Alice",
+				Admin:    true,
+			},
+		},
+		{
+			name:     "Embedded struct fields are promoted",
+			input:    `{"id": "u-1", "Created": "yesterday"}`,
+			expected: testPerson{testBase: testBase{ID: "u-1", Created: "yesterday"}},
+		},
+		{
+			name:     "Ignored and dash-named fields",
+			input:    `{"Ignored": "no", "-": "yes", "private": "no"}`,
+			expected: testPerson{Dash: "yes"},
+		},
+		{
+			name:     "Unknown keys are skipped",
+			input:    `{"unknown": {"deep": [1, 2, {"x": null}]}, "name": "Bob"}`,
+			expected: testPerson{Name: "Bob"},
+		},
+		{
+			name:  "Nested struct and pointer to struct",
+			input: `{"address": {"street": "Main", "city": "Springfield"}, "previous": {"street": "Elm"}}`,
+			expected: testPerson{
+				Address:  testAddress{Street: "Main", City: "Springfield"},
+				Previous: &testAddress{Street: "Elm"},
+			},
+		},
+		{
+			name:     "Pointer to string and null pointer",
+			input:    `{"nickname": "ally", "previous": null}`,
+			expected: testPerson{Nickname: &nickname},
+		},
+		{
+			name:  "Slice of structs",
+			input: `{"friends": [{"name": "Carol"}, {"name": "Dave", "friends": []}]}`,
+			expected: testPerson{
+				Friends: []testPerson{
+					{Name: "Carol"},
+					{Name: "Dave", Friends: []testPerson{}},
+				},
+			},
+		},
+		{
+			name:  "Map and array fields",
+			input: `{"extra": {"k": [1, "v"]}, "tags": ["a", "b", "c"]}`,
+			expected: testPerson{
+				Extra: map[string]any{"k": []any{float64(1), "v"}},
+				Tags:  [2]string{"a", "b"},
+			},
+		},
+		{
+			name:    "Type mismatch in field",
+			input:   `{"age": "thirty"}`,
+			wantErr: true,
+		},
+		{
+			name:    "Array into struct field",
+			input:   `{"address": []}`,
+			wantErr: true,
+		},
+		{
+			name:    "Syntax error inside skipped value",
+			input:   `{"unknown": [1, }`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result testPerson
+			err := Decode(tt.input, &result)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Decode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Decode() = %+v, want %+v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDecodeStructPointer(t *testing.T) {
+	var result *testAddress
+	if err := Decode(`{"street": "Main"}`, &result); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if result == nil || result.Street != "Main" {
+		t.Errorf("Decode() = %+v, want &{Street:Main}", result)
+	}
+
+	var list []*testAddress
+	if err := Decode(`[{"street": "A"}, null, {"city": "C"}]`, &list); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	expected := []*testAddress{{Street: "A"}, nil, {City: "C"}}
+	if !reflect.DeepEqual(list, expected) {
+		t.Errorf("Decode() = %+v, want %+v", list, expected)
+	}
+}
+
+func TestDecodeStructFieldConflicts(t *testing.T) {
+	type A struct{ Name string }
+	type B struct{ Name string }
+	type C struct {
+		Name string `json:"Name"`
+	}
+	type ambiguous struct {
+		A
+		B
+	}
+	type tagWins struct {
+		A
+		C
+	}
+	type shallowWins struct {
+		A
+		Name string
+	}
+
+	var amb ambiguous
+	if err := Decode(`{"Name": "x"}`, &amb); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if amb.A.Name != "" || amb.B.Name != "" {
+		t.Errorf("ambiguous field was set: %+v", amb)
+	}
+
+	var tw tagWins
+	if err := Decode(`{"Name": "x"}`, &tw); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if tw.C.Name != "x" || tw.A.Name != "" {
+		t.Errorf("tagged field did not win: %+v", tw)
+	}
+
+	var sw shallowWins
+	if err := Decode(`{"Name": "x"}`, &sw); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if sw.Name != "x" || sw.A.Name != "" {
+		t.Errorf("shallow field did not win: %+v", sw)
+	}
+}
+
+func TestDecodeTypedNumbers(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		target   any
+		expected any
+		wantErr  bool
+	}{
+		{name: "int", input: `42`, target: new(int), expected: 42},
+		{name: "negative int", input: `-42`, target: new(int), expected: -42},
+		{name: "int8 max", input: `127`, target: new(int8), expected: int8(127)},
+		{name: "int8 min", input: `-128`, target: new(int8), expected: int8(-128)},
+		{name: "int8 overflow", input: `128`, target: new(int8), wantErr: true},
+		{name: "int16", input: `-32768`, target: new(int16), expected: int16(-32768)},
+		{name: "int32 overflow", input: `2147483648`, target: new(int32), wantErr: true},
+		{name: "int64 exact", input: `9007199254740993`, target: new(int64), expected: int64(9007199254740993)},
+		{name: "int64 max", input: `9223372036854775807`, target: new(int64), expected: int64(9223372036854775807)},
+		{name: "int64 overflow", input: `9223372036854775808`, target: new(int64), wantErr: true},
+		{name: "uint", input: `7`, target: new(uint), expected: uint(7)},
+		{name: "uint8 overflow", input: `256`, target: new(uint8), wantErr: true},
+		{name: "uint16", input: `65535`, target: new(uint16), expected: uint16(65535)},
+		{name: "uint32", input: `4294967295`, target: new(uint32), expected: uint32(4294967295)},
+		{name: "uint64 max", input: `18446744073709551615`, target: new(uint64), expected: uint64(18446744073709551615)},
+		{name: "negative uint", input: `-1`, target: new(uint), wantErr: true},
+		{name: "negative zero uint", input: `-0`, target: new(uint), expected: uint(0)},
+		{name: "exponent integer", input: `1e3`, target: new(int), expected: 1000},
+		{name: "integral fraction", input: `2.50e1`, target: new(int), expected: 25},
+		{name: "negative exponent integer", input: `1200e-2`, target: new(int), expected: 12},
+		{name: "fraction into int", input: `1.5`, target: new(int), wantErr: true},
+		{name: "small fraction into int", input: `5e-1`, target: new(int), wantErr: true},
+		{name: "huge exponent into int", input: `1e400`, target: new(int64), wantErr: true},
+		{name: "zero with huge exponent", input: `0e400`, target: new(int64), expected: int64(0)},
+		{name: "float32", input: `1.5`, target: new(float32), expected: float32(1.5)},
+		{name: "float32 overflow", input: `1e39`, target: new(float32), wantErr: true},
+		{name: "float64 overflow", input: `1e309`, target: new(float64), wantErr: true},
+		{name: "pointer to int", input: `3`, target: new(*int), expected: func() *int { n := 3; return &n }()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Decode(tt.input, tt.target)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Decode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				var numErr *NumberError
+				if !errors.As(err, &numErr) {
+					t.Errorf("Decode() error = %T, want *NumberError", err)
+				}
+				return
+			}
+			got := reflect.ValueOf(tt.target).Elem().Interface()
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Decode() = %v (%T), want %v (%T)", got, got, tt.expected, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDecodeNumberErrorPosition(t *testing.T) {
+	var config struct {
+		Port  uint16 `json:"port"`
+		Limit int32  `json:"limit"`
+	}
+	err := Decode(`{"port": 8080, "limit": 3.5}`, &config)
+	var numErr *NumberError
+	if !errors.As(err, &numErr) {
+		t.Fatalf("Decode() error = %v, want *NumberError", err)
+	}
+	if numErr.Position != 24 || numErr.Line != 1 || numErr.Column != 25 || numErr.Value != "3.5" || numErr.Type != reflect.TypeOf(int32(0)) {
+		t.Errorf("Decode() error = %+v, want position 24, value 3.5, type int32", numErr)
+	}
+	if config.Port != 8080 {
+		t.Errorf("Port = %d, want 8080", config.Port)
+	}
+
+	err = Decode(`[1, 300]`, new([]uint8))
+	if !errors.As(err, &numErr) || numErr.Position != 4 {
+		t.Errorf("Decode() error = %v, want overflow at position 4", err)
+	}
+}
+
+func TestDecodeErrorLocations(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		target any
+		line   int
+		column int
+		msg    string
+	}{
+		{
+			name:   "Missing colon",
+			input:  "{\n  \"port\" 8080\n}",
+			target: new(map[string]any),
+			line:   2, column: 10,
+			msg: `2:10: expected ':' after object key, found "8080"`,
+		},
+		{
+			name:   "Missing comma in object",
+			input:  `{"a": 1 "b": 2}`,
+			target: new(map[string]any),
+			line:   1, column: 9,
+			msg: `1:9: expected ',' or '}' after object value, found "\"b\""`,
+		},
+		{
+			name:   "Bad object key",
+			input:  `{"a": 1, 2: 3}`,
+			target: new(map[string]any),
+			line:   1, column: 10,
+			msg: `1:10: expected object key after ',', found "2"`,
+		},
+		{
+			name:   "Missing comma in array",
+			input:  "[\n  1\n  2\n]",
+			target: new([]any),
+			line:   3, column: 3,
+			msg: `3:3: expected ',' or ']' after array element, found "2"`,
+		},
+		{
+			name:   "Invalid character after array element",
+			input:  "[1,\n 2 x]",
+			target: new([]any),
+			line:   2, column: 4,
+			msg: `2:4: expected ',' or ']' after array element, found "x"`,
+		},
+		{
+			name:   "Invalid character after object value",
+			input:  `{"a": 1 @}`,
+			target: new(map[string]any),
+			line:   1, column: 9,
+			msg: `1:9: expected ',' or '}' after object value, found "@"`,
+		},
+		{
+			name:   "Malformed string after object key",
+			input:  `{"a" "b\q"}`,
+			target: new(map[string]any),
+			line:   1, column: 6,
+			msg: `1:6: expected ':' after object key, found "\""`,
+		},
+		{
+			name:   "Unclosed array",
+			input:  "[1,\n 2",
+			target: new([]any),
+			line:   2, column: 3,
+			msg: `2:3: missing closing bracket for array opened at 1:1`,
+		},
+		{
+			name:   "Unexpected closing bracket",
+			input:  `{"a": ]}`,
+			target: new(map[string]any),
+			line:   1, column: 7,
+			msg: `1:7: unexpected "]" looking for beginning of value`,
+		},
+		{
+			name:   "Scanner error inside value",
+			input:  `{"a": [1, 2, @]}`,
+			target: new(map[string]any),
+			line:   1, column: 14,
+			msg: `1:14: invalid character '@' looking for beginning of value`,
+		},
+		{
+			name:   "Type mismatch",
+			input:  "{\n  \"age\": \"thirty\"\n}",
+			target: new(testPerson),
+			line:   2, column: 10,
+			msg: `2:10: cannot assign string to float64`,
+		},
+		{
+			name:   "Null into number",
+			input:  `[null]`,
+			target: new([]int),
+			line:   1, column: 2,
+			msg: `1:2: cannot assign null to int`,
+		},
+		{
+			name:   "Number overflow",
+			input:  "[\n 1,\n 300]",
+			target: new([]uint8),
+			line:   3, column: 2,
+			msg: `3:2: number 300 overflows uint8`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Decode(tt.input, tt.target)
+			if err == nil {
+				t.Fatalf("Decode() succeeded, want error")
+			}
+			var loc scanner.Location
+			var syntaxErr scanner.SyntaxError
+			var typeErr *TypeError
+			var numErr *NumberError
+			switch {
+			case errors.As(err, &syntaxErr):
+				loc = syntaxErr.Location
+			case errors.As(err, &typeErr):
+				loc = typeErr.Location
+			case errors.As(err, &numErr):
+				loc = numErr.Location
+			default:
+				t.Fatalf("Decode() error = %T %v, want a located error", err, err)
+			}
+			if loc.Line != tt.line || loc.Column != tt.column {
+				t.Errorf("error at %d:%d, want %d:%d", loc.Line, loc.Column, tt.line, tt.column)
+			}
+			if err.Error() != tt.msg {
+				t.Errorf("Decode() error = %q, want %q", err.Error(), tt.msg)
+			}
+			if !strings.Contains(loc.Excerpt, "^") {
+				t.Errorf("excerpt has no caret: %q", loc.Excerpt)
+			}
+		})
+	}
+}
+
+func TestDecodeInvalidTarget(t *testing.T) {
+	var invalid *InvalidDecodeError
+	if err := Decode(`{}`, map[string]any{}); !errors.As(err, &invalid) {
+		t.Errorf("Decode() error = %v, want *InvalidDecodeError", err)
+	}
+	if err := Decode(`{}`, nil); !errors.As(err, &invalid) {
+		t.Errorf("Decode() error = %v, want *InvalidDecodeError", err)
+	}
+	var nilPtr *map[string]any
+	if err := Decode(`{}`, nilPtr); !errors.As(err, &invalid) {
+		t.Errorf("Decode() error = %v, want *InvalidDecodeError", err)
+	}
+}
+
 // Benchmark tests
 func BenchmarkDecodeSimpleObject(b *testing.B) {
 	json := `{"name": "John", "age": 30, "city": "New York"}`
@@ -640,3 +1138,48 @@ func BenchmarkDecodeNestedStructure(b *testing.B) {
 		_ = Decode(json, &result)
 	}
 }
+
+// FuzzDecode checks that no input makes Decode panic, and that whatever it
+// accepts survives a round trip through Encode.
+func FuzzDecode(f *testing.F) {
+	seeds := []string{
+		`{"name": "Alice", "age": 30, "friends": [{"name": "Bob"}]}`,
+		`[1, -2.5e3, "xé\n", true, false, null, {}, []]`,
+		`"😀"`,
+		`{"address": {"street": "Main"}, "tags": ["a", "b", "c"]}`,
+		strings.Repeat("[", 100) + strings.Repeat("]", 100),
+		strings.Repeat(`{"a":`, 50),
+		`1e400`,
+		`-`,
+		`tru`,
+		`"\u12`,
+		`{"a" 1}`,
+		`[1,]`,
+		"\xff",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		var v any
+		if err := DecodeWithOptions(input, &v, MaxDepth(200)); err == nil {
+			encoded, err := Encode(v)
+			if err != nil {
+				t.Fatalf("Encode(%#v) error = %v", v, err)
+			}
+			var again any
+			if err := Decode(encoded, &again); err != nil {
+				t.Fatalf("Decode(%s) error = %v", encoded, err)
+			}
+			if !reflect.DeepEqual(v, again) {
+				t.Fatalf("round trip changed %#v into %#v", v, again)
+			}
+		}
+
+		var person testPerson
+		_ = DecodeWithOptions(input, &person, MaxDepth(200))
+		var numbers []int8
+		_ = DecodeWithOptions(input, &numbers, MaxDepth(200))
+	})
+}
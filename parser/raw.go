@@ -0,0 +1,31 @@
+package parser
+
+import (
+	"reflect"
+
+	"github.com/Ronit-Raj/json-parser/scanner"
+)
+
+// RawMessage is the source text of a JSON value, kept as it was written.
+// Decoding into a RawMessage checks the syntax of the value but builds
+// nothing, so that part of a document can be decoded later, once it is
+// known what it holds. Encode writes a RawMessage back out verbatim.
+type RawMessage []byte
+
+var rawMessageType = reflect.TypeOf(RawMessage(nil))
+
+// UnmarshalJSON stores a copy of data in m.
+func (m *RawMessage) UnmarshalJSON(data []byte) error {
+	*m = append((*m)[:0], data...)
+	return nil
+}
+
+// checkValid reports a syntax error unless text holds exactly one JSON
+// value.
+func checkValid(text string) error {
+	d := &decodeState{scan: scanner.New(text), opts: newOptions(nil)}
+	if err := d.value(reflect.Value{}); err != nil {
+		return err
+	}
+	return d.expectEnd()
+}
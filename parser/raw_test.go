@@ -0,0 +1,153 @@
+package parser
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Ronit-Raj/json-parser/scanner"
+)
+
+func TestDecodeRawMessage(t *testing.T) {
+	type envelope struct {
+		Type    string     `json:"type"`
+		Payload RawMessage `json:"payload"`
+	}
+	input := `{"payload": {"amount": 12.50, "tags": ["a", "b"] }, "type": "charge"}`
+	var env envelope
+	if err := Decode(input, &env); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if env.Type != "charge" {
+		t.Errorf("Type = %q", env.Type)
+	}
+	if want := `{"amount": 12.50, "tags": ["a", "b"] }`; string(env.Payload) != want {
+		t.Errorf("Payload = %s, want %s", env.Payload, want)
+	}
+
+	// The payload decodes later once its type is known.
+	var charge struct {
+		Amount Number   `json:"amount"`
+		Tags   []string `json:"tags"`
+	}
+	if err := Decode(string(env.Payload), &charge); err != nil {
+		t.Fatalf("Decode() of payload error = %v", err)
+	}
+	if charge.Amount != "12.50" || !reflect.DeepEqual(charge.Tags, []string{"a", "b"}) {
+		t.Errorf("payload = %+v", charge)
+	}
+}
+
+func TestDecodeRawMessageTargets(t *testing.T) {
+	var m map[string]RawMessage
+	if err := Decode(`{"a": 1, "b": "two", "c": null, "d": [true]}`, &m); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	want := map[string]RawMessage{"a": RawMessage(`1`), "b": RawMessage(`"two"`), "c": RawMessage(`null`), "d": RawMessage(`[true]`)}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("Decode() = %q, want %q", m, want)
+	}
+
+	var list []*RawMessage
+	if err := Decode(`[null, {"x": 1}]`, &list); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if len(list) != 2 || list[0] != nil || string(*list[1]) != `{"x": 1}` {
+		t.Errorf("Decode() = %v", list)
+	}
+
+	// Decoding reuses the slice without aliasing the input.
+	raw := make(RawMessage, 0, 64)
+	if err := Decode(`[1, 2]`, &raw); err != nil || string(raw) != `[1, 2]` || cap(raw) != 64 {
+		t.Errorf("Decode() = %s (cap %d), %v", raw, cap(raw), err)
+	}
+}
+
+func TestDecodeRawMessageValidates(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  []Option
+	}{
+		{name: "missing comma", input: `{"p": [1 2]}`},
+		{name: "unterminated", input: `{"p": {"a": 1}`},
+		{name: "duplicate keys", input: `{"p": {"a": 1, "a": 2}}`, opts: []Option{DuplicateKeys(RejectDuplicateKeys)}},
+		{name: "too deep", input: `{"p": [[[1]]]}`, opts: []Option{MaxDepth(3)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v struct {
+				P RawMessage `json:"p"`
+			}
+			if err := DecodeWithOptions(tt.input, &v, tt.opts...); err == nil {
+				t.Errorf("DecodeWithOptions() = %s, want an error", v.P)
+			}
+		})
+	}
+
+	// Values that only fail to fit a Go type are fine: nothing is built.
+	var v struct {
+		P RawMessage `json:"p"`
+	}
+	if err := Decode(`{"p": 1e400}`, &v); err != nil || string(v.P) != `1e400` {
+		t.Errorf("Decode() = %s, %v", v.P, err)
+	}
+}
+
+func TestDecoderRawMessage(t *testing.T) {
+	blob := `{"blob": "` + strings.Repeat("z", 3*4096) + `"}`
+	dec := NewDecoder(strings.NewReader(`{"id": 1, "body": ` + blob + `} {"id": 2, "body": []}`))
+	for _, want := range []string{blob, `[]`} {
+		var msg struct {
+			ID   int        `json:"id"`
+			Body RawMessage `json:"body"`
+		}
+		if err := dec.Decode(&msg); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		if string(msg.Body) != want {
+			t.Errorf("Body has %d bytes, want %d", len(msg.Body), len(want))
+		}
+	}
+}
+
+func TestEncodeRawMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    any
+		expected string
+		wantErr  bool
+	}{
+		{name: "verbatim", input: RawMessage(`{"b": 1,  "a": [ 2 ]}`), expected: `{"b": 1,  "a": [ 2 ]}`},
+		{name: "in struct", input: struct {
+			Type    string     `json:"type"`
+			Payload RawMessage `json:"payload"`
+		}{Type: "x", Payload: RawMessage(`[1,2]`)}, expected: `{"type":"x","payload":[1,2]}`},
+		{name: "empty", input: RawMessage(nil), expected: `null`},
+		{name: "pointer", input: &RawMessage{'7'}, expected: `7`},
+		{name: "invalid", input: RawMessage(`{"a":}`), wantErr: true},
+		{name: "two values", input: RawMessage(`1 2`), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Encode(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Encode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				var syntaxErr scanner.SyntaxError
+				if !errors.As(err, &syntaxErr) {
+					t.Errorf("Encode() error = %v, want a wrapped SyntaxError", err)
+				}
+				return
+			}
+			if got != tt.expected {
+				t.Errorf("Encode() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"io"
+
+	"github.com/Ronit-Raj/json-parser/scanner"
+)
+
+// Decoder reads and decodes JSON values from an input stream. Input is pulled
+// from the reader in chunks as the scanner advances, so the whole document
+// never has to be held in memory at once.
+type Decoder struct {
+	scan *scanner.Scanner
+	opts options
+}
+
+// NewDecoder returns a Decoder that reads from r and applies opts to every
+// value it decodes.
+func NewDecoder(r io.Reader, opts ...Option) *Decoder {
+	o := newOptions(opts)
+	return &Decoder{scan: o.setUp(scanner.NewReader(r)), opts: o}
+}
+
+// Decode reads the next JSON value from the stream and stores it in the value
+// pointed to by v, following the same rules as the package level Decode.
+//
+// The stream may hold several top-level values, optionally separated by
+// whitespace, and each call decodes the next one. Once only whitespace is
+// left Decode returns io.EOF.
+func (dec *Decoder) Decode(v any) error {
+	token, err := dec.scan.PeekToken()
+	if err != nil {
+		return err
+	}
+	if token.TypeOfToken == scanner.EOF {
+		return io.EOF
+	}
+	d := &decodeState{scan: dec.scan, opts: dec.opts}
+	return d.unmarshal(v)
+}
+
+// InputOffset returns the byte offset in the stream just past the last value
+// decoded.
+func (dec *Decoder) InputOffset() int {
+	return dec.scan.Offset()
+}
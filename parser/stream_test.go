@@ -0,0 +1,126 @@
+package parser
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestDecoderConcatenatedValues(t *testing.T) {
+	input := ` {"a": [1, 2]}[3]"four" 5
+true null{}`
+	expected := []any{
+		map[string]any{"a": []any{float64(1), float64(2)}},
+		[]any{float64(3)},
+		"four",
+		float64(5),
+		true,
+		nil,
+		map[string]any{},
+	}
+
+	readers := map[string]func() io.Reader{
+		"whole":    func() io.Reader { return strings.NewReader(input) },
+		"one byte": func() io.Reader { return iotest.OneByteReader(strings.NewReader(input)) },
+		"half":     func() io.Reader { return iotest.HalfReader(strings.NewReader(input)) },
+	}
+	for name, newReader := range readers {
+		t.Run(name, func(t *testing.T) {
+			dec := NewDecoder(newReader())
+			var got []any
+			for {
+				var v any
+				err := dec.Decode(&v)
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("Decode() error = %v", err)
+				}
+				got = append(got, v)
+			}
+			if !reflect.DeepEqual(got, expected) {
+				t.Errorf("Decode() = %#v, want %#v", got, expected)
+			}
+			if dec.InputOffset() != len(input) {
+				t.Errorf("InputOffset() = %d, want %d", dec.InputOffset(), len(input))
+			}
+		})
+	}
+}
+
+func TestDecoderStruct(t *testing.T) {
+	input := `{"name": "Alice", "friends": [{"name": "Bob"}]} {"name": "Carol"}`
+	dec := NewDecoder(iotest.OneByteReader(strings.NewReader(input)))
+
+	var first, second testPerson
+	if err := dec.Decode(&first); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if err := dec.Decode(&second); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if first.Name != "Alice" || len(first.Friends) != 1 || first.Friends[0].Name != "Bob" || second.Name != "Carol" {
+		t.Errorf("Decode() = %+v, %+v", first, second)
+	}
+	if err := dec.Decode(&second); err != io.EOF {
+		t.Errorf("Decode() error = %v, want io.EOF", err)
+	}
+}
+
+func TestDecoderLongTokens(t *testing.T) {
+	long := strings.Repeat("abc\\n", 10000)
+	number := "1" + strings.Repeat("0", 300)
+	input := `["` + long + `", ` + number + `]`
+
+	var got []any
+	if err := NewDecoder(strings.NewReader(input)).Decode(&got); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if got[0] != strings.Repeat("abc\n", 10000) {
+		t.Errorf("long string decoded incorrectly")
+	}
+	if got[1] != 1e300 {
+		t.Errorf("long number = %v, want 1e300", got[1])
+	}
+}
+
+func TestDecoderErrors(t *testing.T) {
+	t.Run("syntax error keeps stream position", func(t *testing.T) {
+		dec := NewDecoder(iotest.OneByteReader(strings.NewReader(`[1, 2] [3 4]`)))
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		if err := dec.Decode(&v); err == nil {
+			t.Errorf("Decode() of [3 4] succeeded")
+		}
+	})
+
+	t.Run("truncated stream", func(t *testing.T) {
+		var v any
+		err := NewDecoder(strings.NewReader(`{"a": [1, 2`)).Decode(&v)
+		if err == nil || err == io.EOF {
+			t.Errorf("Decode() error = %v, want syntax error", err)
+		}
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		readErr := errors.New("connection reset")
+		r := io.MultiReader(strings.NewReader(`{"a": `), iotest.ErrReader(readErr))
+		var v any
+		if err := NewDecoder(r).Decode(&v); !errors.Is(err, readErr) {
+			t.Errorf("Decode() error = %v, want %v", err, readErr)
+		}
+	})
+
+	t.Run("empty stream", func(t *testing.T) {
+		var v any
+		if err := NewDecoder(strings.NewReader("  \n")).Decode(&v); err != io.EOF {
+			t.Errorf("Decode() error = %v, want io.EOF", err)
+		}
+	})
+}
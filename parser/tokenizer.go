@@ -0,0 +1,375 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"unicode/utf8"
+
+	"github.com/Ronit-Raj/json-parser/scanner"
+)
+
+// TokenKind is the kind of a Token returned by a Tokenizer.
+type TokenKind int8
+
+// The kinds start at 1, so the zero Token returned with an error has no kind.
+const (
+	TokenBeginObject TokenKind = iota + 1 // {
+	TokenEndObject                        // }
+	TokenBeginArray                       // [
+	TokenEndArray                         // ]
+	TokenKey                              // an object key
+	TokenString                           // a string value
+	TokenNumber                           // a number value
+	TokenBool                             // true or false
+	TokenNull                             // null
+)
+
+var tokenKindNames = [...]string{
+	TokenBeginObject: "'{'",
+	TokenEndObject:   "'}'",
+	TokenBeginArray:  "'['",
+	TokenEndArray:    "']'",
+	TokenKey:         "key",
+	TokenString:      "string",
+	TokenNumber:      "number",
+	TokenBool:        "bool",
+	TokenNull:        "null",
+}
+
+func (k TokenKind) String() string {
+	if int(k) < len(tokenKindNames) && tokenKindNames[k] != "" {
+		return tokenKindNames[k]
+	}
+	return fmt.Sprintf("TokenKind(%d)", int(k))
+}
+
+// Token is one token of a JSON document as a Tokenizer sees it.
+type Token struct {
+	Kind TokenKind
+	// Value is the decoded text of a key or string, the number exactly as
+	// written, or "true", "false" or "null". It is empty for delimiters.
+	Value string
+	// Depth is the number of arrays and objects around the token. The
+	// delimiters of an array or object have the depth of the array or
+	// object itself, so a top-level '[' and its ']' are at depth 0.
+	Depth int
+	// Offset is the byte offset of the token in the input.
+	Offset int
+}
+
+// tokenizerState is what a Tokenizer expects next.
+type tokenizerState int8
+
+const (
+	stateValue        tokenizerState = iota // a value
+	stateFirstElement                       // a value or ']', after '['
+	stateFirstKey                           // a key or '}', after '{'
+	stateKey                                // a key, after ',' in an object
+	stateColon                              // ':' and then a value, after a key
+	stateCommaOrEnd                         // ',' or the end of the array or object, after a value
+)
+
+// Tokenizer reads a JSON document from an io.Reader one token at a time,
+// checking the grammar as it goes, so documents of any size can be walked
+// without building them in memory. Decode and Skip consume whole values, for
+// example to decode the elements of a huge array one by one:
+//
+//	tok := parser.NewTokenizer(r)
+//	tok.Next() // [
+//	for tok.More() {
+//		var record Record
+//		if err := tok.Decode(&record); err != nil {
+//			return err
+//		}
+//	}
+//	tok.Next() // ]
+//
+// Like a Decoder, a Tokenizer reads any number of top-level values.
+type Tokenizer struct {
+	scan  *scanner.Scanner
+	opts  options
+	stack []TokenKind // TokenBeginArray or TokenBeginObject for every open container
+	state tokenizerState
+	err   error // the first error, returned from then on
+}
+
+// NewTokenizer returns a Tokenizer that reads from r. Of the options, Decode
+// honors all of them and the Tokenizer itself honors MaxDepth and JSON5. In
+// JSON5 mode the Value of a number token is the number as written, such as
+// 0x1F or +.5.
+func NewTokenizer(r io.Reader, opts ...Option) *Tokenizer {
+	o := newOptions(opts)
+	return &Tokenizer{scan: o.setUp(scanner.NewReader(r)), opts: o}
+}
+
+// Depth returns the number of arrays and objects currently open.
+func (t *Tokenizer) Depth() int {
+	return len(t.stack)
+}
+
+// InputOffset returns the byte offset just past the last token read.
+func (t *Tokenizer) InputOffset() int {
+	return t.scan.Offset()
+}
+
+// Next returns the next token. Commas and colons are checked and consumed
+// but not returned. At the end of the input Next returns io.EOF.
+func (t *Tokenizer) Next() (Token, error) {
+	if err := t.separator(); err != nil {
+		return Token{}, err
+	}
+	tok, err := t.scan.NextToken()
+	if err != nil {
+		return Token{}, t.fail(err)
+	}
+
+	switch t.state {
+	case stateFirstKey, stateKey:
+		if tok.TypeOfToken == scanner.END_OBJECT && t.state == stateFirstKey {
+			return t.end(tok)
+		}
+		key, ok := t.opts.objectKey(tok)
+		if !ok {
+			return Token{}, t.syntaxError(tok.Start, "expected object key, found %s", describeToken(tok))
+		}
+		t.state = stateColon
+		return Token{Kind: TokenKey, Value: key.StringVal, Depth: len(t.stack), Offset: tok.Start}, nil
+	case stateCommaOrEnd:
+		return t.end(tok)
+	case stateFirstElement:
+		if tok.TypeOfToken == scanner.END_ARRAY {
+			return t.end(tok)
+		}
+	}
+
+	token := Token{Value: tok.Raw, Depth: len(t.stack), Offset: tok.Start}
+	switch tok.TypeOfToken {
+	case scanner.BEGIN_OBJECT, scanner.BEGIN_ARRAY:
+		if len(t.stack) >= t.opts.maxDepth {
+			return Token{}, t.syntaxError(tok.Start, "exceeded maximum nesting depth of %d", t.opts.maxDepth)
+		}
+		token.Kind, token.Value, t.state = TokenBeginArray, "", stateFirstElement
+		if tok.TypeOfToken == scanner.BEGIN_OBJECT {
+			token.Kind, t.state = TokenBeginObject, stateFirstKey
+		}
+		t.stack = append(t.stack, token.Kind)
+		return token, nil
+	case scanner.STRING:
+		token.Kind, token.Value = TokenString, tok.StringVal
+	case scanner.NUMBER:
+		token.Kind = TokenNumber
+	case scanner.LITERAL_TRUE, scanner.LITERAL_FALSE:
+		token.Kind = TokenBool
+	case scanner.LITERAL_NULL:
+		token.Kind = TokenNull
+	case scanner.EOF:
+		if len(t.stack) == 0 {
+			return Token{}, io.EOF
+		}
+		return Token{}, t.syntaxError(tok.Start, "unexpected end of input, expected a value")
+	default:
+		return Token{}, t.syntaxError(tok.Start, "unexpected %s looking for beginning of value", describeToken(tok))
+	}
+	t.afterValue()
+	return token, nil
+}
+
+// More reports whether the current array or object has another element. At
+// the top level it reports whether there is another value. If the input is
+// malformed More reports false, and the next call to Next, Decode or Skip
+// returns the error.
+func (t *Tokenizer) More() bool {
+	if t.separator() != nil {
+		return false
+	}
+	tok, err := t.scan.PeekToken()
+	if err != nil {
+		return false
+	}
+	switch tok.TypeOfToken {
+	case scanner.END_ARRAY, scanner.END_OBJECT, scanner.EOF:
+		return false
+	}
+	return true
+}
+
+// Decode decodes the next value into the value pointed to by v, following
+// the same rules as the package level Decode. If a key is next, the key is
+// skipped and its value decoded.
+func (t *Tokenizer) Decode(v any) error {
+	return t.consumeValue(true, func(d *decodeState) error {
+		return d.unmarshal(v)
+	})
+}
+
+// Skip reads the next value, checking its syntax, and throws it away. If a
+// key is next, Skip skips the key and its value. Only the current token is
+// kept in memory, so Skip can pass over values of any size.
+func (t *Tokenizer) Skip() error {
+	return t.consumeValue(false, func(d *decodeState) error {
+		// Duplicate keys don't matter in a value that is thrown away, so
+		// only a syntax error can stop Skip.
+		d.opts.duplicateKeys = LastKeyWins
+		return d.value(reflect.Value{})
+	})
+}
+
+// consumeValue positions t before the next value and hands it to consume.
+// If rewind is set and consume fails because the value does not fit its
+// target, the rest of the value is skipped so that t can carry on with the
+// next one. That means holding on to the whole value, so only Decode, which
+// can fail that way, sets rewind.
+func (t *Tokenizer) consumeValue(rewind bool, consume func(*decodeState) error) error {
+	if err := t.separator(); err != nil {
+		return err
+	}
+	if t.state == stateFirstKey || t.state == stateKey {
+		if _, err := t.Next(); err != nil {
+			return err
+		}
+		if err := t.separator(); err != nil {
+			return err
+		}
+	}
+	tok, err := t.scan.PeekToken()
+	if err != nil {
+		return t.fail(err)
+	}
+	switch tok.TypeOfToken {
+	case scanner.END_ARRAY, scanner.END_OBJECT:
+		// Misuse rather than bad input, so t is still usable.
+		return scanner.SyntaxError{Msg: "expected a value, found " + describeToken(tok), Location: t.scan.Locate(tok.Start)}
+	case scanner.EOF:
+		if len(t.stack) == 0 {
+			return io.EOF
+		}
+	}
+
+	if rewind {
+		t.scan.Hold(tok.Start)
+		defer t.scan.Release()
+	}
+	err = consume(&decodeState{scan: t.scan, opts: t.opts, depth: len(t.stack)})
+	switch err.(type) {
+	case nil:
+	case *InvalidDecodeError:
+		t.scan.Rewind(tok.Start)
+		return err
+	case *TypeError, *NumberError, *UnknownFieldError, *DuplicateKeyError, *UnmarshalerError:
+		t.scan.Rewind(tok.Start)
+		skip := &decodeState{scan: t.scan, opts: t.opts, depth: len(t.stack)}
+		skip.opts.duplicateKeys = LastKeyWins // already reported
+		if skipErr := skip.value(reflect.Value{}); skipErr != nil {
+			return t.fail(skipErr)
+		}
+	default:
+		return t.fail(err)
+	}
+	t.afterValue()
+	return err
+}
+
+// separator consumes the ':' or ',' the grammar requires before the next
+// token, if any.
+func (t *Tokenizer) separator() error {
+	if t.err != nil {
+		return t.err
+	}
+	switch t.state {
+	case stateColon:
+		tok, err := t.scan.NextToken()
+		if err != nil {
+			return t.malformed(tok, err, "':' after object key")
+		}
+		if tok.TypeOfToken != scanner.NAME_SEPARATOR {
+			return t.syntaxError(tok.Start, "expected ':' after object key, found %s", describeToken(tok))
+		}
+		t.state = stateValue
+	case stateCommaOrEnd:
+		tok, err := t.scan.PeekToken()
+		if err != nil {
+			tok, _ = t.scan.NextToken()
+			_, expected := t.closing()
+			return t.malformed(tok, err, expected)
+		}
+		if tok.TypeOfToken != scanner.VALUE_SEPARATOR {
+			// Without a ',' the array or object must end here.
+			return t.checkEnd(tok)
+		}
+		t.scan.NextToken()
+		inObject := t.stack[len(t.stack)-1] == TokenBeginObject
+		switch {
+		case t.opts.json5 && inObject:
+			// A trailing comma may come before the end.
+			t.state = stateFirstKey
+		case t.opts.json5:
+			t.state = stateFirstElement
+		case inObject:
+			t.state = stateKey
+		default:
+			t.state = stateValue
+		}
+	}
+	return nil
+}
+
+// closing returns the token that ends the current array or object, and what
+// may follow a value in it.
+func (t *Tokenizer) closing() (scanner.TokenType, string) {
+	if t.stack[len(t.stack)-1] == TokenBeginObject {
+		return scanner.END_OBJECT, "',' or '}' after object value"
+	}
+	return scanner.END_ARRAY, "',' or ']' after array element"
+}
+
+// checkEnd returns a syntax error unless tok ends the current array or
+// object.
+func (t *Tokenizer) checkEnd(tok scanner.Token) error {
+	if want, expected := t.closing(); tok.TypeOfToken != want {
+		return t.syntaxError(tok.Start, "expected %s, found %s", expected, describeToken(tok))
+	}
+	return nil
+}
+
+// end handles tok where the current array or object may end.
+func (t *Tokenizer) end(tok scanner.Token) (Token, error) {
+	if err := t.checkEnd(tok); err != nil {
+		return Token{}, err
+	}
+	kind := TokenEndArray
+	if t.stack[len(t.stack)-1] == TokenBeginObject {
+		kind = TokenEndObject
+	}
+	t.stack = t.stack[:len(t.stack)-1]
+	t.afterValue()
+	return Token{Kind: kind, Depth: len(t.stack), Offset: tok.Start}, nil
+}
+
+// malformed returns the error for tok, which failed to scan with err where
+// expected belongs. A token that began is described by its first character.
+func (t *Tokenizer) malformed(tok scanner.Token, err error, expected string) error {
+	if tok.Raw == "" {
+		return t.fail(err)
+	}
+	_, size := utf8.DecodeRuneInString(tok.Raw)
+	return t.syntaxError(tok.Start, "expected %s, found %q", expected, tok.Raw[:size])
+}
+
+// afterValue moves on from a complete value.
+func (t *Tokenizer) afterValue() {
+	t.state = stateValue
+	if len(t.stack) > 0 {
+		t.state = stateCommaOrEnd
+	}
+}
+
+func (t *Tokenizer) syntaxError(offset int, format string, args ...any) error {
+	return t.fail(scanner.SyntaxError{Msg: fmt.Sprintf(format, args...), Location: t.scan.Locate(offset)})
+}
+
+// fail records err so that every later call returns it too.
+func (t *Tokenizer) fail(err error) error {
+	t.err = err
+	return err
+}
@@ -0,0 +1,372 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/Ronit-Raj/json-parser/scanner"
+)
+
+// collectTokens reads every token from tok until io.EOF or an error.
+func collectTokens(tok *Tokenizer) ([]Token, error) {
+	var tokens []Token
+	for {
+		token, err := tok.Next()
+		if err == io.EOF {
+			return tokens, nil
+		}
+		if err != nil {
+			return tokens, err
+		}
+		tokens = append(tokens, token)
+	}
+}
+
+func TestTokenizer(t *testing.T) {
+	input := `{"a": [1, "x", true, null], "b\n": {}, "c": -1.5e3} ["second"]`
+	tokens, err := collectTokens(NewTokenizer(strings.NewReader(input)))
+	if err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+	expected := []Token{
+		{Kind: TokenBeginObject, Depth: 0, Offset: 0},
+		{Kind: TokenKey, Value: "a", Depth: 1, Offset: 1},
+		{Kind: TokenBeginArray, Depth: 1, Offset: 6},
+		{Kind: TokenNumber, Value: "1", Depth: 2, Offset: 7},
+		{Kind: TokenString, Value: "x", Depth: 2, Offset: 10},
+		{Kind: TokenBool, Value: "true", Depth: 2, Offset: 15},
+		{Kind: TokenNull, Value: "null", Depth: 2, Offset: 21},
+		{Kind: TokenEndArray, Depth: 1, Offset: 25},
+		{Kind: TokenKey, Value: "b\n", Depth: 1, Offset: 28},
+		{Kind: TokenBeginObject, Depth: 1, Offset: 35},
+		{Kind: TokenEndObject, Depth: 1, Offset: 36},
+		{Kind: TokenKey, Value: "c", Depth: 1, Offset: 39},
+		{Kind: TokenNumber, Value: "-1.5e3", Depth: 1, Offset: 44},
+		{Kind: TokenEndObject, Depth: 0, Offset: 50},
+		{Kind: TokenBeginArray, Depth: 0, Offset: 52},
+		{Kind: TokenString, Value: "second", Depth: 1, Offset: 53},
+		{Kind: TokenEndArray, Depth: 0, Offset: 61},
+	}
+	if !reflect.DeepEqual(tokens, expected) {
+		t.Errorf("Next() tokens =\n%v\nwant\n%v", tokens, expected)
+	}
+}
+
+func TestTokenizerErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		position int
+		msg      string // a part of the message, if set
+	}{
+		{name: "missing comma", input: `[1 2]`, position: 3},
+		{name: "invalid character after element", input: `[1 @]`, position: 3,
+			msg: `expected ',' or ']' after array element, found "@"`},
+		{name: "malformed string after key", input: `{"a" "\q"}`, position: 5,
+			msg: `expected ':' after object key, found "\""`},
+		{name: "missing colon", input: `{"a" 1}`, position: 5},
+		{name: "number key", input: `{1: 2}`, position: 1},
+		{name: "trailing comma in array", input: `[1,]`, position: 3},
+		{name: "trailing comma in object", input: `{"a": 1,}`, position: 8},
+		{name: "mismatched end", input: `[1}`, position: 2},
+		{name: "unexpected end", input: `{"a": [`, position: 7},
+		{name: "stray colon", input: `[:]`, position: 1},
+		{name: "invalid character", input: `[@]`, position: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tok := NewTokenizer(strings.NewReader(tt.input))
+			_, err := collectTokens(tok)
+			var syntaxErr scanner.SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Next() error = %v, want SyntaxError", err)
+			}
+			if syntaxErr.Position != tt.position {
+				t.Errorf("error at %d, want %d (%v)", syntaxErr.Position, tt.position, err)
+			}
+			if !strings.Contains(syntaxErr.Msg, tt.msg) {
+				t.Errorf("error %q, want %q", syntaxErr.Msg, tt.msg)
+			}
+			if _, again := tok.Next(); again != err {
+				t.Errorf("Next() after an error = %v, want %v again", again, err)
+			}
+		})
+	}
+}
+
+func TestTokenizerMissingComma(t *testing.T) {
+	tests := []struct {
+		input    string
+		position int
+	}{
+		{input: `[1 2 3]`, position: 3},
+		{input: `{"a":1 "b":2}`, position: 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			tok := NewTokenizer(strings.NewReader(tt.input))
+			tok.Next()
+			n := 0
+			for tok.More() {
+				if err := tok.Decode(new(any)); err != nil {
+					t.Fatalf("Decode() error = %v", err)
+				}
+				n++
+			}
+			if n != 1 {
+				t.Errorf("More() reported %d values, want 1", n)
+			}
+			_, err := tok.Next()
+			var syntaxErr scanner.SyntaxError
+			if !errors.As(err, &syntaxErr) || syntaxErr.Position != tt.position {
+				t.Errorf("Next() error = %v, want a SyntaxError at %d", err, tt.position)
+			}
+			if again := tok.Skip(); again != err {
+				t.Errorf("Skip() after an error = %v, want %v again", again, err)
+			}
+		})
+	}
+}
+
+func TestTokenKindZero(t *testing.T) {
+	var token Token
+	if token.Kind == TokenBeginObject {
+		t.Errorf("the zero Token is a '{'")
+	}
+	if got := token.Kind.String(); got != "TokenKind(0)" {
+		t.Errorf("String() = %q, want TokenKind(0)", got)
+	}
+}
+
+func TestTokenizerMaxDepth(t *testing.T) {
+	tok := NewTokenizer(strings.NewReader(`[[[1]]]`), MaxDepth(2))
+	if _, err := collectTokens(tok); err == nil || !strings.Contains(err.Error(), "maximum nesting depth of 2") {
+		t.Errorf("Next() error = %v, want a nesting depth error", err)
+	}
+}
+
+func TestTokenizerJSON5(t *testing.T) {
+	input := "{a: [0x1F, 'x',], null: .5, /* c */} // end"
+	tokens, err := collectTokens(NewTokenizer(iotest.HalfReader(strings.NewReader(input)), JSON5()))
+	if err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+	expected := []Token{
+		{Kind: TokenBeginObject, Depth: 0, Offset: 0},
+		{Kind: TokenKey, Value: "a", Depth: 1, Offset: 1},
+		{Kind: TokenBeginArray, Depth: 1, Offset: 4},
+		{Kind: TokenNumber, Value: "0x1F", Depth: 2, Offset: 5},
+		{Kind: TokenString, Value: "x", Depth: 2, Offset: 11},
+		{Kind: TokenEndArray, Depth: 1, Offset: 15},
+		{Kind: TokenKey, Value: "null", Depth: 1, Offset: 18},
+		{Kind: TokenNumber, Value: ".5", Depth: 1, Offset: 24},
+		{Kind: TokenEndObject, Depth: 0, Offset: 35},
+	}
+	if !reflect.DeepEqual(tokens, expected) {
+		t.Errorf("Next() tokens =\n%v\nwant\n%v", tokens, expected)
+	}
+
+	for _, input := range []string{`[1,,]`, `{a: 1,,}`, `{1: 2}`} {
+		if _, err := collectTokens(NewTokenizer(strings.NewReader(input), JSON5())); err == nil {
+			t.Errorf("Next() accepted %q", input)
+		}
+	}
+}
+
+func TestTokenizerDecodeElements(t *testing.T) {
+	type record struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	const count = 2000
+	var sb strings.Builder
+	sb.WriteString(`{"meta": {"skip": [1, {"deep": true}]}, "records": [`)
+	for i := 0; i < count; i++ {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(&sb, `{"id": %d, "name": "record %d"}`, i, i)
+	}
+	sb.WriteString(`], "after": 1}`)
+
+	tok := NewTokenizer(iotest.OneByteReader(strings.NewReader(sb.String())))
+	if token, err := tok.Next(); err != nil || token.Kind != TokenBeginObject {
+		t.Fatalf("Next() = %v, %v, want '{'", token, err)
+	}
+	if err := tok.Skip(); err != nil { // "meta" and its value
+		t.Fatalf("Skip() error = %v", err)
+	}
+	if token, err := tok.Next(); err != nil || token.Kind != TokenKey || token.Value != "records" {
+		t.Fatalf("Next() = %v, %v, want key records", token, err)
+	}
+	if token, err := tok.Next(); err != nil || token.Kind != TokenBeginArray {
+		t.Fatalf("Next() = %v, %v, want '['", token, err)
+	}
+	n := 0
+	for tok.More() {
+		var r record
+		if err := tok.Decode(&r); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		if r.ID != n || r.Name != fmt.Sprintf("record %d", n) {
+			t.Fatalf("record %d = %+v", n, r)
+		}
+		if tok.Depth() != 2 {
+			t.Fatalf("Depth() = %d inside the array, want 2", tok.Depth())
+		}
+		n++
+	}
+	if n != count {
+		t.Errorf("decoded %d records, want %d", n, count)
+	}
+	if token, err := tok.Next(); err != nil || token.Kind != TokenEndArray {
+		t.Fatalf("Next() = %v, %v, want ']'", token, err)
+	}
+	var after int
+	if err := tok.Decode(&after); err != nil || after != 1 {
+		t.Errorf("Decode() = %d, %v, want the value of after", after, err)
+	}
+	if token, err := tok.Next(); err != nil || token.Kind != TokenEndObject || token.Depth != 0 {
+		t.Errorf("Next() = %v, %v, want '}'", token, err)
+	}
+	if tok.More() {
+		t.Errorf("More() at the end of the input = true")
+	}
+	if _, err := tok.Next(); err != io.EOF {
+		t.Errorf("Next() at the end = %v, want io.EOF", err)
+	}
+}
+
+func TestTokenizerRecoversFromTypeErrors(t *testing.T) {
+	type record struct {
+		ID int `json:"id"`
+	}
+	tok := NewTokenizer(strings.NewReader(`[{"id": 1}, {"id": "two", "x": [1, 2]}, {"id": 1e400}, {"id": 4}]`))
+	tok.Next()
+	var ids []int
+	var errs []error
+	for tok.More() {
+		var r record
+		if err := tok.Decode(&r); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ids = append(ids, r.ID)
+	}
+	if !reflect.DeepEqual(ids, []int{1, 4}) {
+		t.Errorf("decoded ids %v, want [1 4]", ids)
+	}
+	var typeErr *TypeError
+	var numErr *NumberError
+	if len(errs) != 2 || !errors.As(errs[0], &typeErr) || !errors.As(errs[1], &numErr) {
+		t.Errorf("errors = %v, want a *TypeError and a *NumberError", errs)
+	}
+	if token, err := tok.Next(); err != nil || token.Kind != TokenEndArray {
+		t.Errorf("Next() = %v, %v, want ']'", token, err)
+	}
+
+	// Passing a non-pointer leaves the value in place.
+	tok = NewTokenizer(strings.NewReader(`[7]`))
+	tok.Next()
+	var n int
+	var invalidErr *InvalidDecodeError
+	if err := tok.Decode(n); !errors.As(err, &invalidErr) {
+		t.Errorf("Decode(non-pointer) error = %v", err)
+	}
+	if err := tok.Decode(&n); err != nil || n != 7 {
+		t.Errorf("Decode() = %d, %v, want 7", n, err)
+	}
+}
+
+func TestTokenizerMisuse(t *testing.T) {
+	tok := NewTokenizer(strings.NewReader(`[]`))
+	tok.Next()
+	if err := tok.Skip(); err == nil {
+		t.Errorf("Skip() at the end of an array succeeded")
+	}
+	// The tokenizer is still usable.
+	if token, err := tok.Next(); err != nil || token.Kind != TokenEndArray {
+		t.Errorf("Next() = %v, %v, want ']'", token, err)
+	}
+	if err := tok.Skip(); err != io.EOF {
+		t.Errorf("Skip() at the end of the input = %v, want io.EOF", err)
+	}
+}
+
+func TestTokenizerDecodeUnmarshaler(t *testing.T) {
+	value := `{"long": "` + strings.Repeat("x", 10000) + `", "list": [1, 2, 3]}`
+	tok := NewTokenizer(iotest.OneByteReader(strings.NewReader(`[` + value + `, ` + value + `]`)))
+	tok.Next()
+	for i := 0; tok.More(); i++ {
+		var r testRaw
+		if err := tok.Decode(&r); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		if r.raw != value {
+			t.Errorf("value %d: got %d bytes, want %d", i, len(r.raw), len(value))
+		}
+	}
+	if token, err := tok.Next(); err != nil || token.Kind != TokenEndArray {
+		t.Errorf("Next() = %v, %v, want ']'", token, err)
+	}
+}
+
+// heapReader produces an array of n strings of 1 KiB without ever holding
+// the whole document in memory, noting the most heap in use as it goes.
+type heapReader struct {
+	remaining int
+	pending   string
+	started   bool
+	maxHeap   uint64
+}
+
+func (r *heapReader) Read(p []byte) (int, error) {
+	if r.pending == "" {
+		item := `"` + strings.Repeat("x", 1022) + `"`
+		switch {
+		case !r.started:
+			r.pending, r.started = "[", true
+		case r.remaining > 1:
+			r.pending = item + ","
+		case r.remaining == 1:
+			r.pending = item + "]"
+		default:
+			return 0, io.EOF
+		}
+		if r.remaining%1024 == 0 {
+			var stats runtime.MemStats
+			runtime.ReadMemStats(&stats)
+			r.maxHeap = max(r.maxHeap, stats.HeapAlloc)
+		}
+		r.remaining--
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+func TestTokenizerSkipStreams(t *testing.T) {
+	const size = 64 << 20
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	r := &heapReader{remaining: size >> 10}
+	tok := NewTokenizer(r)
+	if err := tok.Skip(); err != nil {
+		t.Fatalf("Skip() error = %v", err)
+	}
+	if _, err := tok.Next(); err != io.EOF {
+		t.Errorf("Next() after the array = %v, want io.EOF", err)
+	}
+	if grown := r.maxHeap - min(r.maxHeap, stats.HeapAlloc); grown > size/4 {
+		t.Errorf("heap grew by %d bytes skipping %d", grown, size)
+	}
+}
@@ -0,0 +1,109 @@
+package parser
+
+import (
+	"encoding"
+	"reflect"
+
+	"github.com/Ronit-Raj/json-parser/scanner"
+)
+
+// Unmarshaler is implemented by types that decode themselves. UnmarshalJSON
+// is given the exact source text of one JSON value, which may be null, and
+// owns the slice.
+type Unmarshaler interface {
+	UnmarshalJSON([]byte) error
+}
+
+var (
+	unmarshalerType     = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// unmarshaler hands the value starting with token to v if v implements
+// Unmarshaler, or to its UnmarshalText method if the value is a string and v
+// implements encoding.TextUnmarshaler. It reports whether it did.
+func (d *decodeState) unmarshaler(token scanner.Token, v reflect.Value) (bool, error) {
+	u, tu := findUnmarshaler(v, token.TypeOfToken == scanner.LITERAL_NULL)
+	switch {
+	case u != nil:
+		raw, err := d.rawValue()
+		if err != nil {
+			return true, err
+		}
+		if err := u.UnmarshalJSON([]byte(raw)); err != nil {
+			return true, &UnmarshalerError{Type: reflect.TypeOf(u), Err: err, Location: d.scan.Locate(token.Start)}
+		}
+		return true, nil
+	case tu != nil && token.TypeOfToken == scanner.STRING:
+		d.scan.NextToken()
+		if err := tu.UnmarshalText([]byte(token.StringVal)); err != nil {
+			return true, &UnmarshalerError{Type: reflect.TypeOf(tu), Err: err, Location: d.scan.Locate(token.Start)}
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// rawValue consumes the next value, checking its syntax, and returns its
+// source text.
+func (d *decodeState) rawValue() (string, error) {
+	token, err := d.scan.PeekToken()
+	if err != nil {
+		return "", err
+	}
+	d.scan.Hold(token.Start)
+	defer d.scan.Release()
+	if err := d.value(reflect.Value{}); err != nil {
+		return "", err
+	}
+	return d.scan.Slice(token.Start, d.scan.Offset()), nil
+}
+
+// findUnmarshaler walks down v through pointers, allocating them as it goes
+// like indirect, and returns the first Unmarshaler or TextUnmarshaler on the
+// way. When decodingNull is set it stops at the last settable pointer, which
+// null sets to nil without asking the type.
+func findUnmarshaler(v reflect.Value, decodingNull bool) (Unmarshaler, encoding.TextUnmarshaler) {
+	for {
+		if v.Kind() != reflect.Ptr {
+			if !v.CanAddr() {
+				return nil, nil
+			}
+			return asUnmarshaler(v.Addr())
+		}
+		if decodingNull && v.CanSet() {
+			return nil, nil
+		}
+		if v.IsNil() {
+			if !v.CanSet() {
+				return nil, nil
+			}
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		if u, tu := asUnmarshaler(v); u != nil || tu != nil {
+			return u, tu
+		}
+		v = v.Elem()
+	}
+}
+
+// asUnmarshaler returns the pointer p as an Unmarshaler or TextUnmarshaler.
+// The math/big types implement both, but Decode reads numbers into them
+// itself, exactly and with exponents, so they are left out.
+func asUnmarshaler(p reflect.Value) (Unmarshaler, encoding.TextUnmarshaler) {
+	t := p.Type()
+	switch t.Elem() {
+	case bigIntType, bigFloatType, bigRatType:
+		return nil, nil
+	}
+	if !p.CanInterface() {
+		return nil, nil
+	}
+	if t.Implements(unmarshalerType) {
+		return p.Interface().(Unmarshaler), nil
+	}
+	if t.Implements(textUnmarshalerType) {
+		return nil, p.Interface().(encoding.TextUnmarshaler)
+	}
+	return nil, nil
+}
@@ -0,0 +1,157 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// testRaw records the source text it was decoded from.
+type testRaw struct {
+	raw string
+}
+
+func (r *testRaw) UnmarshalJSON(data []byte) error {
+	r.raw = string(data)
+	return nil
+}
+
+// testCents decodes a decimal amount such as 12.34 into whole cents.
+type testCents int64
+
+func (c *testCents) UnmarshalJSON(data []byte) error {
+	whole, frac, _ := strings.Cut(string(data), ".")
+	var n int64
+	if _, err := fmt.Sscan(whole+(frac + "00")[:2], &n); err != nil {
+		return err
+	}
+	*c = testCents(n)
+	return nil
+}
+
+// testLevel is an enum decoded from its name.
+type testLevel int
+
+func (l *testLevel) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "low":
+		*l = 1
+	case "high":
+		*l = 2
+	default:
+		return errors.New("unknown level " + string(text))
+	}
+	return nil
+}
+
+var errTestFailure = errors.New("always fails")
+
+type testFailing struct{}
+
+func (testFailing) UnmarshalJSON([]byte) error {
+	return errTestFailure
+}
+
+func TestDecodeUnmarshaler(t *testing.T) {
+	type order struct {
+		ID      testRaw     `json:"id"`
+		Total   testCents   `json:"total"`
+		Level   testLevel   `json:"level"`
+		Levels  []testLevel `json:"levels"`
+		Addr    netip.Addr  `json:"addr"`
+		Extra   *testRaw    `json:"extra"`
+		Missing *testRaw    `json:"missing"`
+		Count   testLevel   `json:"count"`
+	}
+	input := `{"id": { "nested" : [1, "two"] }, "total": 12.34, "level": "high", "levels": ["low", "high"],` +
+		` "addr": "10.0.0.1", "extra": null, "missing": 7, "count": 3}`
+	var o order
+	if err := Decode(input, &o); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	want := order{
+		ID:      testRaw{raw: `{ "nested" : [1, "two"] }`},
+		Total:   1234,
+		Level:   2,
+		Levels:  []testLevel{1, 2},
+		Addr:    netip.MustParseAddr("10.0.0.1"),
+		Missing: &testRaw{raw: `7`},
+		Count:   3,
+	}
+	if !reflect.DeepEqual(o, want) {
+		t.Errorf("Decode() = %+v, want %+v", o, want)
+	}
+}
+
+func TestDecodeUnmarshalerNull(t *testing.T) {
+	// A null pointer is set to nil without asking the type, but a value
+	// that is not a pointer gets to see the null.
+	p := &testRaw{raw: "before"}
+	if err := Decode(`null`, &p); err != nil || p != nil {
+		t.Errorf("Decode() = %v, %v, want nil", p, err)
+	}
+	var r testRaw
+	if err := Decode(`null`, &r); err != nil || r.raw != "null" {
+		t.Errorf("Decode() = %q, %v, want null", r.raw, err)
+	}
+	var l testLevel = 2
+	if err := Decode(`null`, &l); err == nil {
+		t.Errorf("Decode() of null into a TextUnmarshaler succeeded")
+	}
+}
+
+func TestDecodeUnmarshalerErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		target   any
+		position int
+		wrapped  error
+	}{
+		{name: "unmarshal json", input: `{"a": [1, 2]}`, target: &map[string]testFailing{}, position: 6, wrapped: errTestFailure},
+		{name: "unmarshal text", input: `["low", "medium"]`, target: &[]testLevel{}, position: 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Decode(tt.input, tt.target)
+			var unmarshalerErr *UnmarshalerError
+			if !errors.As(err, &unmarshalerErr) {
+				t.Fatalf("Decode() error = %v, want *UnmarshalerError", err)
+			}
+			if unmarshalerErr.Position != tt.position {
+				t.Errorf("error at %d, want %d", unmarshalerErr.Position, tt.position)
+			}
+			if tt.wrapped != nil && !errors.Is(err, tt.wrapped) {
+				t.Errorf("Decode() error = %v does not wrap %v", err, tt.wrapped)
+			}
+		})
+	}
+
+	t.Run("syntax errors come first", func(t *testing.T) {
+		var r testRaw
+		err := Decode(`{"a": [1 2]}`, &r)
+		var unmarshalerErr *UnmarshalerError
+		if err == nil || errors.As(err, &unmarshalerErr) || r.raw != "" {
+			t.Errorf("Decode() = %q, %v, want a syntax error", r.raw, err)
+		}
+	})
+}
+
+func TestDecoderUnmarshalerAcrossRefills(t *testing.T) {
+	value := `{"long": "` + strings.Repeat("x", 10000) + `", "list": [1, 2, 3]}`
+	dec := NewDecoder(iotest.OneByteReader(strings.NewReader(value + " " + value)))
+	for i := 0; i < 2; i++ {
+		var r testRaw
+		if err := dec.Decode(&r); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		if r.raw != value {
+			t.Errorf("value %d: got %d bytes, want %d", i, len(r.raw), len(value))
+		}
+	}
+}
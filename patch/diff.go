@@ -0,0 +1,182 @@
+package patch
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/Ronit-Raj/json-parser/parser"
+	"github.com/Ronit-Raj/json-parser/pointer"
+)
+
+// maxLCSCells bounds the table Diff builds to match up the elements of two
+// arrays, which needs a cell for every pair of elements. Longer arrays are
+// compared element by element instead, which is correct but may give a
+// longer patch.
+const maxLCSCells = 1 << 20
+
+// Diff returns a patch that turns a into b, both documents as parser.Decode
+// builds them into an interface value, so that Apply(a, Diff(a, b)) is equal
+// to b. The patch only touches what changed: it descends into objects and
+// arrays that appear in both, and matches up array elements by their longest
+// common subsequence, so inserting or removing one element of an array is a
+// single operation. Objects with no key in common are replaced as a whole.
+//
+// The patch uses only add, remove and replace, and its values are shared
+// with b.
+func Diff(a, b any) Patch {
+	var p Patch
+	diff(&p, pointer.Pointer{}, a, b)
+	return p
+}
+
+// diff appends to p the operations that turn a into b at path.
+func diff(p *Patch, path pointer.Pointer, a, b any) {
+	if Equal(a, b) {
+		return
+	}
+	if am, bm := members(a), members(b); am != nil && bm != nil {
+		diffObjects(p, path, a, b, am, bm)
+		return
+	}
+	if as, ok := a.([]any); ok {
+		if bs, ok := b.([]any); ok {
+			diffArrays(p, path, as, bs)
+			return
+		}
+	}
+	*p = append(*p, Operation{Op: "replace", Path: path.String(), Value: b})
+}
+
+func diffObjects(p *Patch, path pointer.Pointer, a, b any, am, bm map[string]any) {
+	common := false
+	for key := range am {
+		if _, ok := bm[key]; ok {
+			common = true
+			break
+		}
+	}
+	if !common {
+		*p = append(*p, Operation{Op: "replace", Path: path.String(), Value: b})
+		return
+	}
+	for _, key := range keys(a) {
+		if _, ok := bm[key]; !ok {
+			*p = append(*p, Operation{Op: "remove", Path: child(path, key).String()})
+		}
+	}
+	for _, key := range keys(b) {
+		if av, ok := am[key]; ok {
+			diff(p, child(path, key), av, bm[key])
+		} else {
+			*p = append(*p, Operation{Op: "add", Path: child(path, key).String(), Value: bm[key]})
+		}
+	}
+}
+
+// diffArrays turns a into b by keeping the elements they have in common and
+// editing the runs of elements between them. Within a run, elements of a and
+// b are paired up and diffed in place, and the rest of the longer side is
+// removed or added.
+func diffArrays(p *Patch, path pointer.Pointer, a, b []any) {
+	// i is the index in the array as patched so far.
+	i, x, y := 0, 0, 0
+	for _, m := range append(commonElements(a, b), [2]int{len(a), len(b)}) {
+		dels, ins := a[x:m[0]], b[y:m[1]]
+		n := min(len(dels), len(ins))
+		for j := 0; j < n; j++ {
+			diff(p, child(path, strconv.Itoa(i)), dels[j], ins[j])
+			i++
+		}
+		for range dels[n:] {
+			*p = append(*p, Operation{Op: "remove", Path: child(path, strconv.Itoa(i)).String()})
+		}
+		for _, v := range ins[n:] {
+			*p = append(*p, Operation{Op: "add", Path: child(path, strconv.Itoa(i)).String(), Value: v})
+			i++
+		}
+		x, y = m[0]+1, m[1]+1
+		i++
+	}
+}
+
+// commonElements returns the index pairs of the elements a and b have in
+// common, in order: their common prefix and suffix and, if the arrays are
+// small enough, the longest common subsequence of what lies between.
+func commonElements(a, b []any) [][2]int {
+	var prefix [][2]int
+	start := 0
+	for start < len(a) && start < len(b) && Equal(a[start], b[start]) {
+		prefix = append(prefix, [2]int{start, start})
+		start++
+	}
+	endA, endB := len(a), len(b)
+	var suffix [][2]int
+	for endA > start && endB > start && Equal(a[endA-1], b[endB-1]) {
+		endA--
+		endB--
+		suffix = append(suffix, [2]int{endA, endB})
+	}
+	slices.Reverse(suffix)
+
+	middle := lcs(a[start:endA], b[start:endB])
+	for i := range middle {
+		middle[i][0] += start
+		middle[i][1] += start
+	}
+	return slices.Concat(prefix, middle, suffix)
+}
+
+// lcs returns the index pairs of a longest common subsequence of a and b, or
+// nothing if the arrays are too large to compare that way.
+func lcs(a, b []any) [][2]int {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 || (n+1)*(m+1) > maxLCSCells {
+		return nil
+	}
+	// length[i*(m+1)+j] is the length of the LCS of a[i:] and b[j:].
+	length := make([]int, (n+1)*(m+1))
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if Equal(a[i], b[j]) {
+				length[i*(m+1)+j] = length[(i+1)*(m+1)+j+1] + 1
+			} else {
+				length[i*(m+1)+j] = max(length[(i+1)*(m+1)+j], length[i*(m+1)+j+1])
+			}
+		}
+	}
+	var pairs [][2]int
+	for i, j := 0, 0; i < n && j < m; {
+		switch {
+		case Equal(a[i], b[j]):
+			pairs = append(pairs, [2]int{i, j})
+			i++
+			j++
+		case length[(i+1)*(m+1)+j] >= length[i*(m+1)+j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return pairs
+}
+
+// keys returns the keys of an object: sorted for a map, and in their own
+// order for a *parser.OrderedObject.
+func keys(v any) []string {
+	if o, ok := v.(*parser.OrderedObject); ok {
+		return o.Keys()
+	}
+	m := v.(map[string]any)
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// child returns the pointer to the member or element token of what path
+// names, without sharing path's backing array.
+func child(path pointer.Pointer, token string) pointer.Pointer {
+	return append(path[:len(path):len(path)], token)
+}
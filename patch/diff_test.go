@@ -0,0 +1,93 @@
+package patch
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     string
+		expected string // the encoded patch
+	}{
+		{name: "equal", a: `{"a": [1, {"b": 2}]}`, b: `{"a": [1, {"b": 2.0}]}`, expected: `[]`},
+		{name: "scalar", a: `1`, b: `"one"`, expected: `[{"op":"replace","path":"","value":"one"}]`},
+		{name: "members", a: `{"a": 1, "b": 2, "c": 3}`, b: `{"a": 1, "c": 4, "d": 5}`,
+			expected: `[{"op":"remove","path":"/b"},{"op":"replace","path":"/c","value":4},{"op":"add","path":"/d","value":5}]`},
+		{name: "nested", a: `{"users": [{"name": "Ada", "langs": ["en"]}]}`, b: `{"users": [{"name": "Ada", "langs": ["en", "fr"]}]}`,
+			expected: `[{"op":"add","path":"/users/0/langs/1","value":"fr"}]`},
+		{name: "no common keys", a: `{"a": 1}`, b: `{"b": 1}`, expected: `[{"op":"replace","path":"","value":{"b":1}}]`},
+		{name: "insert", a: `[1, 2, 3, 4]`, b: `[1, 2, 9, 3, 4]`, expected: `[{"op":"add","path":"/2","value":9}]`},
+		{name: "remove", a: `[1, 2, 3, 4]`, b: `[1, 3, 4]`, expected: `[{"op":"remove","path":"/1"}]`},
+		{name: "remove several", a: `[1, 2, 3, 4, 5]`, b: `[1, 5]`,
+			expected: `[{"op":"remove","path":"/1"},{"op":"remove","path":"/1"},{"op":"remove","path":"/1"}]`},
+		{name: "edit in middle", a: `[0, {"id": 1, "v": "a"}, 2, 3, 4]`, b: `[9, 0, {"id": 1, "v": "b"}, 3, 4]`,
+			expected: `[{"op":"add","path":"/0","value":9},{"op":"replace","path":"/2/v","value":"b"},{"op":"remove","path":"/3"}]`},
+		{name: "key escaping", a: `{"a/b": {"~": 1}}`, b: `{"a/b": {"~": 2}}`, expected: `[{"op":"replace","path":"/a~1b/~0","value":2}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := decode(t, tt.a), decode(t, tt.b)
+			p := Diff(a, b)
+			got, err := Encode(p)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Diff() = %s, want %s", got, tt.expected)
+			}
+			patched, err := Apply(a, p)
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if !Equal(patched, b) {
+				t.Errorf("Apply(a, Diff(a, b)) = %v, want %v", patched, b)
+			}
+		})
+	}
+}
+
+func TestDiffRoundTrip(t *testing.T) {
+	docs := []string{
+		`null`, `[]`, `{}`, `[1, 2, 3]`, `[3, 2, 1]`, `[1, [2, [3]]]`, `["a", "b", "a", "c"]`,
+		`{"a": [1, 2], "b": {"c": null}}`, `{"a": [2, 1], "b": {"c": false, "d": []}}`,
+		`{"b": {"d": [{}]}, "e": "x"}`, `[{"a": 1}, {"a": 2}, {"a": 3}]`, `[{"a": 3}, {"a": 1, "b": 0}]`,
+	}
+	for _, x := range docs {
+		for _, y := range docs {
+			a, b := decode(t, x), decode(t, y)
+			p := Diff(a, b)
+			patched, err := Apply(a, p)
+			if err != nil {
+				t.Fatalf("Apply(%s, Diff(%s, %s)) error = %v", x, x, y, err)
+			}
+			if !Equal(patched, b) {
+				t.Errorf("Apply(%s, Diff(%s, %s)) = %v", x, x, y, patched)
+			}
+		}
+	}
+}
+
+func TestDiffLargeArrays(t *testing.T) {
+	var sa, sb strings.Builder
+	sa.WriteString("[")
+	sb.WriteString("[")
+	for i := 0; i < 2000; i++ {
+		if i > 0 {
+			sa.WriteString(",")
+			sb.WriteString(",")
+		}
+		fmt.Fprint(&sa, i)
+		fmt.Fprint(&sb, (i*7)%2000)
+	}
+	sa.WriteString("]")
+	sb.WriteString("]")
+	a, b := decode(t, sa.String()), decode(t, sb.String())
+	patched, err := Apply(a, Diff(a, b))
+	if err != nil || !Equal(patched, b) {
+		t.Errorf("Apply(a, Diff(a, b)) error = %v, or result differs", err)
+	}
+}
@@ -0,0 +1,24 @@
+package patch
+
+import "fmt"
+
+// OperationError reports an operation of a patch that is malformed or cannot
+// be applied. Err is the reason, such as a *pointer.NotFoundError for a path
+// that names nothing or ErrTestFailed for a failed "test".
+type OperationError struct {
+	Index int    // the position of the operation in the patch
+	Op    string // its op, if known
+	Path  string // its path, if known
+	Err   error
+}
+
+func (e *OperationError) Error() string {
+	if e.Op == "" {
+		return fmt.Sprintf("patch operation %d: %v", e.Index, e.Err)
+	}
+	return fmt.Sprintf("patch operation %d (%s %q): %v", e.Index, e.Op, e.Path, e.Err)
+}
+
+func (e *OperationError) Unwrap() error {
+	return e.Err
+}
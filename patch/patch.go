@@ -0,0 +1,179 @@
+// Package patch implements JSON Patch (RFC 6902), a format for describing
+// changes to a JSON document as a list of operations.
+package patch
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/Ronit-Raj/json-parser/parser"
+	"github.com/Ronit-Raj/json-parser/pointer"
+)
+
+// Operation is one step of a Patch. Path and From are JSON pointers.
+type Operation struct {
+	Op    string // "add", "remove", "replace", "move", "copy" or "test"
+	Path  string
+	From  string // the source of "move" and "copy"
+	Value any    // the value of "add", "replace" and "test"
+}
+
+// Patch is a JSON Patch document: operations applied one after another.
+type Patch []Operation
+
+// Decode parses the text of a JSON Patch document. Operation values are
+// decoded as parser.DecodeWithOptions decodes into an interface value, with
+// opts, and members other than op, path, from and value are ignored.
+func Decode(text string, opts ...parser.Option) (Patch, error) {
+	var ops []any
+	if err := parser.DecodeWithOptions(text, &ops, opts...); err != nil {
+		return nil, err
+	}
+	p := make(Patch, len(ops))
+	for i, v := range ops {
+		fields := members(v)
+		if fields == nil {
+			return nil, &OperationError{Index: i, Err: errors.New("operation is not an object")}
+		}
+		op := &p[i]
+		for _, field := range []struct {
+			name string
+			dst  *string
+		}{{"op", &op.Op}, {"path", &op.Path}, {"from", &op.From}} {
+			if s, ok := fields[field.name].(string); ok {
+				*field.dst = s
+			} else if _, found := fields[field.name]; found {
+				return nil, &OperationError{Index: i, Op: op.Op, Err: fmt.Errorf("%q is not a string", field.name)}
+			}
+		}
+		var hasValue bool
+		op.Value, hasValue = fields["value"]
+		if err := op.validate(); err != nil {
+			return nil, &OperationError{Index: i, Op: op.Op, Path: op.Path, Err: err}
+		}
+		if _, hasPath := fields["path"]; !hasPath {
+			return nil, &OperationError{Index: i, Op: op.Op, Err: errors.New(`missing "path"`)}
+		}
+		if _, hasFrom := fields["from"]; !hasFrom && (op.Op == "move" || op.Op == "copy") {
+			return nil, &OperationError{Index: i, Op: op.Op, Path: op.Path, Err: errors.New(`missing "from"`)}
+		}
+		if !hasValue && (op.Op == "add" || op.Op == "replace" || op.Op == "test") {
+			return nil, &OperationError{Index: i, Op: op.Op, Path: op.Path, Err: errors.New(`missing "value"`)}
+		}
+	}
+	return p, nil
+}
+
+// Encode returns the text of p as a JSON Patch document. Each operation has
+// only the members its op uses.
+func Encode(p Patch) (string, error) {
+	ops := make([]any, len(p))
+	for i, op := range p {
+		o := &parser.OrderedObject{}
+		o.Set("op", op.Op)
+		o.Set("path", op.Path)
+		switch op.Op {
+		case "move", "copy":
+			o.Set("from", op.From)
+		case "add", "replace", "test":
+			o.Set("value", op.Value)
+		}
+		ops[i] = o
+	}
+	return parser.Encode(ops)
+}
+
+// Apply applies p to doc, a document as parser.Decode builds it into an
+// interface value, and returns the patched document. Apply is atomic: it
+// works on a copy, so doc is never modified, and if any operation fails it
+// returns an *OperationError and none of the changes.
+//
+// The result shares nothing with doc or p, so either may be changed later
+// without affecting it.
+func Apply(doc any, p Patch) (any, error) {
+	doc = deepCopy(doc)
+	for i, op := range p {
+		var err error
+		if doc, err = op.apply(doc); err != nil {
+			return nil, &OperationError{Index: i, Op: op.Op, Path: op.Path, Err: err}
+		}
+	}
+	return doc, nil
+}
+
+// ErrTestFailed is the error inside the *OperationError of a "test"
+// operation whose value does not match.
+var ErrTestFailed = errors.New("test failed")
+
+// apply applies op to doc, which it may modify.
+func (op Operation) apply(doc any) (any, error) {
+	if err := op.validate(); err != nil {
+		return nil, err
+	}
+	path, _ := pointer.Parse(op.Path)
+	switch op.Op {
+	case "add":
+		return path.Add(doc, deepCopy(op.Value))
+	case "remove":
+		return path.Delete(doc)
+	case "replace":
+		if _, err := path.Get(doc); err != nil {
+			return nil, err
+		}
+		return path.Set(doc, deepCopy(op.Value))
+	case "move", "copy":
+		from, _ := pointer.Parse(op.From)
+		value, err := from.Get(doc)
+		if err != nil {
+			return nil, err
+		}
+		if op.Op == "copy" {
+			return path.Add(doc, deepCopy(value))
+		}
+		if op.From == op.Path {
+			return doc, nil
+		}
+		if doc, err = from.Delete(doc); err != nil {
+			return nil, err
+		}
+		return path.Add(doc, value)
+	case "test":
+		value, err := path.Get(doc)
+		if err != nil {
+			return nil, err
+		}
+		if !Equal(value, op.Value) {
+			return nil, ErrTestFailed
+		}
+		return doc, nil
+	}
+	panic("unreachable")
+}
+
+// validate checks what can be checked about op without a document.
+func (op Operation) validate() error {
+	switch op.Op {
+	case "add", "remove", "replace", "move", "copy", "test":
+	case "":
+		return errors.New(`missing "op"`)
+	default:
+		return fmt.Errorf("unknown op %q", op.Op)
+	}
+	path, err := pointer.Parse(op.Path)
+	if err != nil {
+		return err
+	}
+	if op.Op != "move" && op.Op != "copy" {
+		return nil
+	}
+	from, err := pointer.Parse(op.From)
+	if err != nil {
+		return err
+	}
+	// A value cannot move into one of its own children.
+	if op.Op == "move" && len(from) < len(path) && slices.Equal(from, path[:len(from)]) {
+		return fmt.Errorf("cannot move %q into itself", op.From)
+	}
+	return nil
+}
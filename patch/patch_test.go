@@ -0,0 +1,221 @@
+package patch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Ronit-Raj/json-parser/parser"
+	"github.com/Ronit-Raj/json-parser/pointer"
+)
+
+func decode(t *testing.T, text string) any {
+	t.Helper()
+	var doc any
+	if err := parser.Decode(text, &doc); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	return doc
+}
+
+// TestApplyRFC runs the examples of RFC 6902 appendix A.
+func TestApplyRFC(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      string
+		patch    string
+		expected string // empty if the patch fails
+	}{
+		{name: "A.1 adding an object member", doc: `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/baz", "value": "qux"}]`, expected: `{"baz": "qux", "foo": "bar"}`},
+		{name: "A.2 adding an array element", doc: `{"foo": ["bar", "baz"]}`,
+			patch: `[{"op": "add", "path": "/foo/1", "value": "qux"}]`, expected: `{"foo": ["bar", "qux", "baz"]}`},
+		{name: "A.3 removing an object member", doc: `{"baz": "qux", "foo": "bar"}`,
+			patch: `[{"op": "remove", "path": "/baz"}]`, expected: `{"foo": "bar"}`},
+		{name: "A.4 removing an array element", doc: `{"foo": ["bar", "qux", "baz"]}`,
+			patch: `[{"op": "remove", "path": "/foo/1"}]`, expected: `{"foo": ["bar", "baz"]}`},
+		{name: "A.5 replacing a value", doc: `{"baz": "qux", "foo": "bar"}`,
+			patch: `[{"op": "replace", "path": "/baz", "value": "boo"}]`, expected: `{"baz": "boo", "foo": "bar"}`},
+		{name: "A.6 moving a value", doc: `{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": "grault"}}`,
+			patch:    `[{"op": "move", "from": "/foo/waldo", "path": "/qux/thud"}]`,
+			expected: `{"foo": {"bar": "baz"}, "qux": {"corge": "grault", "thud": "fred"}}`},
+		{name: "A.7 moving an array element", doc: `{"foo": ["all", "grass", "cows", "eat"]}`,
+			patch: `[{"op": "move", "from": "/foo/1", "path": "/foo/3"}]`, expected: `{"foo": ["all", "cows", "eat", "grass"]}`},
+		{name: "A.8 testing a value: success", doc: `{"baz": "qux", "foo": ["a", 2, "c"]}`,
+			patch:    `[{"op": "test", "path": "/baz", "value": "qux"}, {"op": "test", "path": "/foo/1", "value": 2}]`,
+			expected: `{"baz": "qux", "foo": ["a", 2, "c"]}`},
+		{name: "A.9 testing a value: error", doc: `{"baz": "qux"}`,
+			patch: `[{"op": "test", "path": "/baz", "value": "bar"}]`},
+		{name: "A.10 adding a nested member object", doc: `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/child", "value": {"grandchild": {}}}]`, expected: `{"foo": "bar", "child": {"grandchild": {}}}`},
+		{name: "A.11 ignoring unrecognized elements", doc: `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/baz", "value": "qux", "xyz": 123}]`, expected: `{"foo": "bar", "baz": "qux"}`},
+		{name: "A.12 adding to a nonexistent target", doc: `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/baz/bat", "value": "qux"}]`},
+		{name: "A.14 ~ escape ordering", doc: `{"/": 9, "~1": 10}`,
+			patch: `[{"op": "test", "path": "/~01", "value": 10}]`, expected: `{"/": 9, "~1": 10}`},
+		{name: "A.15 comparing strings and numbers", doc: `{"/": 9, "~1": 10}`,
+			patch: `[{"op": "test", "path": "/~01", "value": "10"}]`},
+		{name: "A.16 adding an array value", doc: `{"foo": ["bar"]}`,
+			patch: `[{"op": "add", "path": "/foo/-", "value": ["abc", "def"]}]`, expected: `{"foo": ["bar", ["abc", "def"]]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Decode(tt.patch)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			got, err := Apply(decode(t, tt.doc), p)
+			if tt.expected == "" {
+				var opErr *OperationError
+				if !errors.As(err, &opErr) {
+					t.Errorf("Apply() = %v, %v, want an OperationError", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if want := decode(t, tt.expected); !Equal(got, want) {
+				t.Errorf("Apply() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      string
+		patch    string
+		expected string
+	}{
+		{name: "copy", doc: `{"a": {"b": [1]}}`, patch: `[{"op": "copy", "from": "/a", "path": "/c"}, {"op": "add", "path": "/c/b/-", "value": 2}]`,
+			expected: `{"a": {"b": [1]}, "c": {"b": [1, 2]}}`},
+		{name: "move to itself", doc: `{"a": 1}`, patch: `[{"op": "move", "from": "/a", "path": "/a"}]`, expected: `{"a": 1}`},
+		{name: "replace root", doc: `{"a": 1}`, patch: `[{"op": "replace", "path": "", "value": [1]}]`, expected: `[1]`},
+		{name: "add null", doc: `{}`, patch: `[{"op": "add", "path": "/a", "value": null}]`, expected: `{"a": null}`},
+		{name: "test object in any order", doc: `{"a": {"x": 1, "y": [2.0]}}`,
+			patch: `[{"op": "test", "path": "/a", "value": {"y": [2], "x": 1.0}}]`, expected: `{"a": {"x": 1, "y": [2]}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Decode(tt.patch)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			got, err := Apply(decode(t, tt.doc), p)
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if want := decode(t, tt.expected); !Equal(got, want) {
+				t.Errorf("Apply() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch Patch
+		index int
+		err   error // wrapped by the OperationError, if not nil
+	}{
+		{name: "failed test", patch: Patch{{Op: "add", Path: "/x", Value: 1.0}, {Op: "test", Path: "/a", Value: 2.0}}, index: 1, err: ErrTestFailed},
+		{name: "replace missing", patch: Patch{{Op: "remove", Path: "/a"}, {Op: "replace", Path: "/b", Value: 1.0}}, index: 1},
+		{name: "remove missing", patch: Patch{{Op: "remove", Path: "/list/3"}}, index: 0},
+		{name: "move into child", patch: Patch{{Op: "move", From: "/list", Path: "/list/0"}}, index: 0},
+		{name: "unknown op", patch: Patch{{Op: "append", Path: "/a"}}, index: 0},
+		{name: "bad pointer", patch: Patch{{Op: "remove", Path: "a"}}, index: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := decode(t, `{"a": 1, "list": [1, 2]}`)
+			got, err := Apply(doc, tt.patch)
+			var opErr *OperationError
+			if !errors.As(err, &opErr) || opErr.Index != tt.index {
+				t.Fatalf("Apply() = %v, %v, want an OperationError for operation %d", got, err, tt.index)
+			}
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Errorf("Apply() error = %v, want %v", err, tt.err)
+			}
+			if want := decode(t, `{"a": 1, "list": [1, 2]}`); !Equal(doc, want) {
+				t.Errorf("Apply() changed doc to %v", doc)
+			}
+		})
+	}
+
+	// A missing path is reported by the pointer package.
+	_, err := Apply(decode(t, `{}`), Patch{{Op: "remove", Path: "/a"}})
+	var notFound *pointer.NotFoundError
+	if !errors.As(err, &notFound) {
+		t.Errorf("Apply() error = %v, want a wrapped *pointer.NotFoundError", err)
+	}
+}
+
+func TestApplyDoesNotShare(t *testing.T) {
+	doc := decode(t, `{"a": [1]}`)
+	value := map[string]any{"x": 1.0}
+	got, err := Apply(doc, Patch{{Op: "add", Path: "/b", Value: value}})
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	value["x"] = 2.0
+	got.(map[string]any)["a"].([]any)[0] = 3.0
+	if want := decode(t, `{"a": [1]}`); !Equal(doc, want) {
+		t.Errorf("doc = %v, want %v", doc, want)
+	}
+	if want := decode(t, `{"a": [3], "b": {"x": 1}}`); !Equal(got, want) {
+		t.Errorf("Apply() = %v, want %v", got, want)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+	}{
+		{name: "not an array", patch: `{"op": "add"}`},
+		{name: "not an object", patch: `[1]`},
+		{name: "missing op", patch: `[{"path": "/a"}]`},
+		{name: "missing path", patch: `[{"op": "remove"}]`},
+		{name: "missing value", patch: `[{"op": "add", "path": "/a"}]`},
+		{name: "missing from", patch: `[{"op": "copy", "path": "/a"}]`},
+		{name: "path not a string", patch: `[{"op": "remove", "path": 1}]`},
+		{name: "bad pointer", patch: `[{"op": "remove", "path": "/a~"}]`},
+		{name: "syntax error", patch: `[{"op": "remove", "path": "/a"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if p, err := Decode(tt.patch); err == nil {
+				t.Errorf("Decode() = %v, want an error", p)
+			}
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	p := Patch{
+		{Op: "add", Path: "/a~1b", Value: nil},
+		{Op: "remove", Path: "/c", Value: "ignored"},
+		{Op: "move", From: "/d", Path: "/e"},
+		{Op: "test", Path: "/f", Value: []any{1.0}},
+	}
+	got, err := Encode(p)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	want := `[{"op":"add","path":"/a~1b","value":null},{"op":"remove","path":"/c"},` +
+		`{"op":"move","path":"/e","from":"/d"},{"op":"test","path":"/f","value":[1]}]`
+	if got != want {
+		t.Errorf("Encode() = %s, want %s", got, want)
+	}
+	back, err := Decode(got)
+	if err != nil || len(back) != len(p) || back[3].Op != "test" || back[2].From != "/d" {
+		t.Errorf("Decode() = %v, %v", back, err)
+	}
+}
@@ -0,0 +1,126 @@
+package patch
+
+import (
+	"github.com/Ronit-Raj/json-parser/parser"
+)
+
+// Equal reports whether a and b, values as parser.Decode builds them into an
+// interface value, are the same JSON value. Objects are equal if they have
+// the same members in any order, whether they are maps or
+// *parser.OrderedObjects, and numbers are equal if they have the same value,
+// so 1, 1.0 and parser.Number("1e0") are all equal.
+func Equal(a, b any) bool {
+	switch a := a.(type) {
+	case nil:
+		return b == nil
+	case bool:
+		b, ok := b.(bool)
+		return ok && a == b
+	case string:
+		b, ok := b.(string)
+		return ok && a == b
+	case float64, parser.Number:
+		return equalNumbers(a, b)
+	case []any:
+		b, ok := b.([]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !Equal(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]any, *parser.OrderedObject:
+		am, bm := members(a), members(b)
+		if am == nil || bm == nil || len(am) != len(bm) {
+			return false
+		}
+		for key, av := range am {
+			bv, ok := bm[key]
+			if !ok || !Equal(av, bv) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// equalNumbers compares two numbers by value. Numbers that do not fit a
+// float64 are only equal to the same text.
+func equalNumbers(a, b any) bool {
+	af, aok := float(a)
+	bf, bok := float(b)
+	if aok && bok {
+		return af == bf
+	}
+	an, aIsNumber := a.(parser.Number)
+	bn, bIsNumber := b.(parser.Number)
+	return aIsNumber && bIsNumber && an == bn
+}
+
+func float(v any) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case parser.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// members returns the members of an object as a map, or nil if v is not an
+// object.
+func members(v any) map[string]any {
+	switch v := v.(type) {
+	case map[string]any:
+		if v == nil {
+			return map[string]any{}
+		}
+		return v
+	case *parser.OrderedObject:
+		m := make(map[string]any, v.Len())
+		for _, member := range v.Members() {
+			m[member.Key] = member.Value
+		}
+		return m
+	}
+	return nil
+}
+
+// deepCopy returns a copy of v that shares no objects or arrays with it.
+func deepCopy(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		if v == nil {
+			return v
+		}
+		m := make(map[string]any, len(v))
+		for key, value := range v {
+			m[key] = deepCopy(value)
+		}
+		return m
+	case *parser.OrderedObject:
+		if v == nil {
+			return v
+		}
+		o := &parser.OrderedObject{}
+		for _, member := range v.Members() {
+			o.Set(member.Key, deepCopy(member.Value))
+		}
+		return o
+	case []any:
+		if v == nil {
+			return v
+		}
+		s := make([]any, len(v))
+		for i, value := range v {
+			s[i] = deepCopy(value)
+		}
+		return s
+	}
+	return v
+}
@@ -0,0 +1,53 @@
+package patch
+
+import (
+	"testing"
+
+	"github.com/Ronit-Raj/json-parser/parser"
+)
+
+func TestEqual(t *testing.T) {
+	ordered := &parser.OrderedObject{}
+	ordered.Set("b", parser.Number("2.0"))
+	ordered.Set("a", []any{"x"})
+	tests := []struct {
+		a, b     any
+		expected bool
+	}{
+		{a: nil, b: nil, expected: true},
+		{a: nil, b: false, expected: false},
+		{a: 1.0, b: parser.Number("1e0"), expected: true},
+		{a: parser.Number("1e400"), b: parser.Number("1e400"), expected: true},
+		{a: parser.Number("1e400"), b: parser.Number("2e400"), expected: false},
+		{a: "1", b: 1.0, expected: false},
+		{a: []any{1.0}, b: []any{1.0, 2.0}, expected: false},
+		{a: map[string]any{"a": []any{"x"}, "b": 2.0}, b: ordered, expected: true},
+		{a: map[string]any{"a": nil}, b: map[string]any{"b": nil}, expected: false},
+		{a: map[string]any{}, b: []any{}, expected: false},
+	}
+
+	for _, tt := range tests {
+		if got := Equal(tt.a, tt.b); got != tt.expected {
+			t.Errorf("Equal(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.expected)
+		}
+		if got := Equal(tt.b, tt.a); got != tt.expected {
+			t.Errorf("Equal(%v, %v) = %v, want %v", tt.b, tt.a, got, tt.expected)
+		}
+	}
+}
+
+func TestDeepCopy(t *testing.T) {
+	ordered := &parser.OrderedObject{}
+	ordered.Set("list", []any{map[string]any{"x": 1.0}})
+	doc := []any{ordered, map[string]any{"y": []any{true}}}
+	copied := deepCopy(doc).([]any)
+	if !Equal(copied, doc) {
+		t.Fatalf("deepCopy() = %v, want %v", copied, doc)
+	}
+	list, _ := copied[0].(*parser.OrderedObject).Get("list")
+	list.([]any)[0].(map[string]any)["x"] = 2.0
+	copied[1].(map[string]any)["y"].([]any)[0] = false
+	if want := []any{ordered, map[string]any{"y": []any{true}}}; !Equal(doc, want) || Equal(copied, doc) {
+		t.Errorf("changing the copy changed the original: %v", doc)
+	}
+}
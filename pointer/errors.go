@@ -0,0 +1,31 @@
+package pointer
+
+import "fmt"
+
+// InvalidPointerError reports a string that is not a valid JSON pointer.
+type InvalidPointerError struct {
+	Pointer string
+	Offset  int // the byte offset of the problem in Pointer
+	Msg     string
+}
+
+func (e *InvalidPointerError) Error() string {
+	return fmt.Sprintf("invalid JSON pointer %q at offset %d: %s", e.Pointer, e.Offset, e.Msg)
+}
+
+// NotFoundError reports a pointer that names no value in the document.
+// Pointer is the part of the pointer up to and including the reference token
+// that could not be resolved, so its parent exists in the document.
+type NotFoundError struct {
+	Pointer string
+	Msg     string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("JSON pointer %q: %s", e.Pointer, e.Msg)
+}
+
+// notFound returns a *NotFoundError for the reference token p[i].
+func (p Pointer) notFound(i int, format string, args ...any) error {
+	return &NotFoundError{Pointer: p[:i+1].String(), Msg: fmt.Sprintf(format, args...)}
+}
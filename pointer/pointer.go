@@ -0,0 +1,321 @@
+// Package pointer implements JSON Pointer (RFC 6901), a string syntax such as
+// "/users/0/name" for naming one value inside a JSON document.
+package pointer
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Ronit-Raj/json-parser/parser"
+)
+
+// Pointer is a parsed JSON pointer: the list of its reference tokens, already
+// unescaped. The empty Pointer names the whole document.
+type Pointer []string
+
+// Parse parses a JSON pointer such as "/users/0/name". It must be empty or
+// start with '/', and '~' may only appear as "~0" (for '~') or "~1" (for '/').
+func Parse(s string) (Pointer, error) {
+	if s == "" {
+		return Pointer{}, nil
+	}
+	if s[0] != '/' {
+		return nil, &InvalidPointerError{Pointer: s, Offset: 0, Msg: "must be empty or start with '/'"}
+	}
+	var p Pointer
+	start := 1
+	for i := 1; i <= len(s); i++ {
+		if i < len(s) && s[i] == '~' && (i+1 == len(s) || (s[i+1] != '0' && s[i+1] != '1')) {
+			return nil, &InvalidPointerError{Pointer: s, Offset: i, Msg: "'~' must be followed by '0' or '1'"}
+		}
+		if i == len(s) || s[i] == '/' {
+			p = append(p, unescape(s[start:i]))
+			start = i + 1
+		}
+	}
+	return p, nil
+}
+
+// New returns the Pointer with the given reference tokens, which are taken
+// as they are and need no escaping.
+func New(tokens ...string) Pointer {
+	return append(Pointer{}, tokens...)
+}
+
+// String returns p in its string form, escaping '~' and '/' in its tokens.
+func (p Pointer) String() string {
+	var sb strings.Builder
+	for _, token := range p {
+		sb.WriteByte('/')
+		sb.WriteString(Escape(token))
+	}
+	return sb.String()
+}
+
+// Escape escapes a reference token for use in a pointer string: '~' becomes
+// "~0" and '/' becomes "~1".
+func Escape(token string) string {
+	if !strings.ContainsAny(token, "~/") {
+		return token
+	}
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// unescape undoes Escape. "~1" must be replaced before "~0", otherwise
+// "~01" would turn into "/" instead of "~1".
+func unescape(token string) string {
+	if !strings.Contains(token, "~") {
+		return token
+	}
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
+
+// Get returns the value the pointer ptr names in doc. See Pointer.Get.
+func Get(doc any, ptr string) (any, error) {
+	p, err := Parse(ptr)
+	if err != nil {
+		return nil, err
+	}
+	return p.Get(doc)
+}
+
+// Set sets the value the pointer ptr names in doc. See Pointer.Set.
+func Set(doc any, ptr string, value any) (any, error) {
+	p, err := Parse(ptr)
+	if err != nil {
+		return nil, err
+	}
+	return p.Set(doc, value)
+}
+
+// Delete removes the value the pointer ptr names from doc. See
+// Pointer.Delete.
+func Delete(doc any, ptr string) (any, error) {
+	p, err := Parse(ptr)
+	if err != nil {
+		return nil, err
+	}
+	return p.Delete(doc)
+}
+
+// Get returns the value p names in doc, a document as parser.Decode builds
+// it into an interface value: objects are map[string]any or
+// *parser.OrderedObject and arrays are []any. If there is no such value, Get
+// returns a *NotFoundError.
+func (p Pointer) Get(doc any) (any, error) {
+	node := doc
+	for i := range p {
+		var err error
+		if node, err = p.child(node, i); err != nil {
+			return nil, err
+		}
+	}
+	return node, nil
+}
+
+// child returns the value p[i] names in node.
+func (p Pointer) child(node any, i int) (any, error) {
+	token := p[i]
+	switch n := node.(type) {
+	case map[string]any:
+		if v, ok := n[token]; ok {
+			return v, nil
+		}
+	case *parser.OrderedObject:
+		if v, ok := n.Get(token); ok {
+			return v, nil
+		}
+	case []any:
+		index, err := p.index(i, len(n), false)
+		if err != nil {
+			return nil, err
+		}
+		return n[index], nil
+	default:
+		return nil, p.notFound(i, "cannot look up %q in %s", token, kind(node))
+	}
+	return nil, p.notFound(i, "no member %q", token)
+}
+
+// Set sets the value p names in doc to value, adding the object member if it
+// does not exist yet. In arrays an index replaces the element, while "-" or
+// the index one past the last element appends. The parent of the value must
+// already exist, otherwise Set returns a *NotFoundError.
+//
+// Like append, Set modifies doc in place where it can and returns the updated
+// document, which callers must use from then on. Setting the empty Pointer
+// returns value itself.
+func (p Pointer) Set(doc, value any) (any, error) {
+	if len(p) == 0 {
+		return value, nil
+	}
+	return p.update(doc, 0, func(parent any, i int) (any, error) {
+		return p.set(parent, i, value)
+	})
+}
+
+// set sets the member or element p[i] of parent to value.
+func (p Pointer) set(parent any, i int, value any) (any, error) {
+	token := p[i]
+	switch n := parent.(type) {
+	case map[string]any:
+		if n == nil {
+			n = make(map[string]any)
+		}
+		n[token] = value
+		return n, nil
+	case *parser.OrderedObject:
+		if n == nil {
+			n = &parser.OrderedObject{}
+		}
+		n.Set(token, value)
+		return n, nil
+	case []any:
+		index, err := p.index(i, len(n), true)
+		if err != nil {
+			return nil, err
+		}
+		if index == len(n) {
+			return append(n, value), nil
+		}
+		n[index] = value
+		return n, nil
+	}
+	return nil, p.notFound(i, "cannot set %q in %s", token, kind(parent))
+}
+
+// Add is Set with the semantics of the "add" operation of JSON Patch
+// (RFC 6902): in arrays an index inserts the value before the element there,
+// moving it and the later elements up by one, instead of replacing it.
+func (p Pointer) Add(doc, value any) (any, error) {
+	if len(p) == 0 {
+		return value, nil
+	}
+	return p.update(doc, 0, func(parent any, i int) (any, error) {
+		if n, ok := parent.([]any); ok {
+			index, err := p.index(i, len(n), true)
+			if err != nil {
+				return nil, err
+			}
+			return slices.Insert(n, index, value), nil
+		}
+		return p.set(parent, i, value)
+	})
+}
+
+// Delete removes the value p names from doc, returning a *NotFoundError if
+// there is none. Later array elements move down by one. Like Set, Delete
+// modifies doc in place where it can and returns the updated document.
+// Deleting the empty Pointer returns nil.
+func (p Pointer) Delete(doc any) (any, error) {
+	if len(p) == 0 {
+		return nil, nil
+	}
+	return p.update(doc, 0, func(parent any, i int) (any, error) {
+		token := p[i]
+		switch n := parent.(type) {
+		case map[string]any:
+			if _, ok := n[token]; !ok {
+				return nil, p.notFound(i, "no member %q", token)
+			}
+			delete(n, token)
+			return n, nil
+		case *parser.OrderedObject:
+			if _, ok := n.Get(token); !ok {
+				return nil, p.notFound(i, "no member %q", token)
+			}
+			n.Delete(token)
+			return n, nil
+		case []any:
+			index, err := p.index(i, len(n), false)
+			if err != nil {
+				return nil, err
+			}
+			return slices.Delete(n, index, index+1), nil
+		}
+		return nil, p.notFound(i, "cannot delete %q from %s", token, kind(parent))
+	})
+}
+
+// update walks from node, which p[:i] names, to the parent of the value p
+// names and replaces the parent with what change returns. Each container on
+// the way is stored back into its own parent, since appending to or deleting
+// from an array may give it a new slice header. p must not be empty.
+func (p Pointer) update(node any, i int, change func(parent any, i int) (any, error)) (any, error) {
+	if i == len(p)-1 {
+		return change(node, i)
+	}
+	child, err := p.child(node, i)
+	if err != nil {
+		return nil, err
+	}
+	child, err = p.update(child, i+1, change)
+	if err != nil {
+		return nil, err
+	}
+	switch n := node.(type) {
+	case map[string]any:
+		n[p[i]] = child
+	case *parser.OrderedObject:
+		n.Set(p[i], child)
+	case []any:
+		index, _ := p.index(i, len(n), false)
+		n[index] = child
+	}
+	return node, nil
+}
+
+// index returns the array index p[i] names in an array of length n. If
+// allowEnd is set, "-" and n itself name the position just past the end.
+func (p Pointer) index(i, n int, allowEnd bool) (int, error) {
+	token := p[i]
+	if token == "-" {
+		if allowEnd {
+			return n, nil
+		}
+		return 0, p.notFound(i, "no element after the end of the array")
+	}
+	index, ok := parseIndex(token)
+	if !ok {
+		return 0, p.notFound(i, "invalid array index %q", token)
+	}
+	if index > n || (index == n && !allowEnd) {
+		return 0, p.notFound(i, "index %s out of range for array of length %d", token, n)
+	}
+	return index, nil
+}
+
+// parseIndex parses an array index, which RFC 6901 allows only as decimal
+// digits without leading zeros. Indexes too large for an int come out as
+// math.MaxInt, which is out of range for any array.
+func parseIndex(token string) (int, bool) {
+	if token == "" || (len(token) > 1 && token[0] == '0') || strings.Trim(token, "0123456789") != "" {
+		return 0, false
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil {
+		return math.MaxInt, true
+	}
+	return index, true
+}
+
+// kind describes the JSON kind of a decoded value for error messages.
+func kind(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case map[string]any, *parser.OrderedObject:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64, parser.Number:
+		return "number"
+	}
+	return "value"
+}
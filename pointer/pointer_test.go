@@ -0,0 +1,264 @@
+package pointer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Ronit-Raj/json-parser/parser"
+)
+
+// rfcDocument is the example document of RFC 6901 section 5.
+const rfcDocument = `{
+	"foo": ["bar", "baz"],
+	"": 0,
+	"a/b": 1,
+	"c%d": 2,
+	"e^f": 3,
+	"g|h": 4,
+	"i\\j": 5,
+	"k\"l": 6,
+	" ": 7,
+	"m~n": 8
+}`
+
+func decode(t *testing.T, text string) any {
+	t.Helper()
+	var doc any
+	if err := parser.Decode(text, &doc); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	return doc
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Pointer
+		offset   int // of the error, or -1
+	}{
+		{input: "", expected: Pointer{}, offset: -1},
+		{input: "/", expected: Pointer{""}, offset: -1},
+		{input: "/foo/0", expected: Pointer{"foo", "0"}, offset: -1},
+		{input: "/a~1b/m~0n", expected: Pointer{"a/b", "m~n"}, offset: -1},
+		{input: "/~01", expected: Pointer{"~1"}, offset: -1},
+		{input: "//", expected: Pointer{"", ""}, offset: -1},
+		{input: "foo", offset: 0},
+		{input: "/a~2", offset: 2},
+		{input: "/a~", offset: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			p, err := Parse(tt.input)
+			if tt.offset >= 0 {
+				var invalidErr *InvalidPointerError
+				if !errors.As(err, &invalidErr) || invalidErr.Offset != tt.offset {
+					t.Errorf("Parse() error = %v, want an InvalidPointerError at %d", err, tt.offset)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if !reflect.DeepEqual(p, tt.expected) {
+				t.Errorf("Parse() = %q, want %q", p, tt.expected)
+			}
+			if s := p.String(); s != tt.input {
+				t.Errorf("String() = %q, want %q", s, tt.input)
+			}
+		})
+	}
+
+	if s := New("a/b", "~", "0").String(); s != "/a~1b/~0/0" {
+		t.Errorf("String() = %q", s)
+	}
+}
+
+func TestGet(t *testing.T) {
+	doc := decode(t, rfcDocument)
+	tests := []struct {
+		pointer  string
+		expected any
+	}{
+		{pointer: "", expected: doc},
+		{pointer: "/foo", expected: []any{"bar", "baz"}},
+		{pointer: "/foo/0", expected: "bar"},
+		{pointer: "/", expected: 0.0},
+		{pointer: "/a~1b", expected: 1.0},
+		{pointer: "/c%d", expected: 2.0},
+		{pointer: "/e^f", expected: 3.0},
+		{pointer: "/g|h", expected: 4.0},
+		{pointer: "/i\\j", expected: 5.0},
+		{pointer: "/k\"l", expected: 6.0},
+		{pointer: "/ ", expected: 7.0},
+		{pointer: "/m~0n", expected: 8.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pointer, func(t *testing.T) {
+			got, err := Get(doc, tt.pointer)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Get() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetNotFound(t *testing.T) {
+	doc := decode(t, `{"users": [{"name": "Ada", "tags": null}], "count": 1}`)
+	tests := []struct {
+		pointer string
+		at      string // the Pointer of the NotFoundError
+	}{
+		{pointer: "/missing", at: "/missing"},
+		{pointer: "/users/1/name", at: "/users/1"},
+		{pointer: "/users/-", at: "/users/-"},
+		{pointer: "/users/01", at: "/users/01"},
+		{pointer: "/users/+0", at: "/users/+0"},
+		{pointer: "/users/99999999999999999999999", at: "/users/99999999999999999999999"},
+		{pointer: "/users/0/tags/0", at: "/users/0/tags/0"},
+		{pointer: "/count/x", at: "/count/x"},
+		{pointer: "/users/0/name/first", at: "/users/0/name/first"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pointer, func(t *testing.T) {
+			_, err := Get(doc, tt.pointer)
+			var notFound *NotFoundError
+			if !errors.As(err, &notFound) {
+				t.Fatalf("Get() error = %v, want a NotFoundError", err)
+			}
+			if notFound.Pointer != tt.at {
+				t.Errorf("error at %q, want %q (%v)", notFound.Pointer, tt.at, err)
+			}
+		})
+	}
+}
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      string
+		pointer  string
+		value    any
+		expected string
+	}{
+		{name: "replace member", doc: `{"a": 1}`, pointer: "/a", value: "x", expected: `{"a": "x"}`},
+		{name: "add member", doc: `{"a": 1}`, pointer: "/b", value: true, expected: `{"a": 1, "b": true}`},
+		{name: "nested", doc: `{"a": [{"b": 1}]}`, pointer: "/a/0/c", value: 2.0, expected: `{"a": [{"b": 1, "c": 2}]}`},
+		{name: "replace element", doc: `[1, 2, 3]`, pointer: "/1", value: "two", expected: `[1, "two", 3]`},
+		{name: "append with dash", doc: `{"a": [1]}`, pointer: "/a/-", value: 2.0, expected: `{"a": [1, 2]}`},
+		{name: "append with length", doc: `{"a": {"b": []}}`, pointer: "/a/b/0", value: 1.0, expected: `{"a": {"b": [1]}}`},
+		{name: "root", doc: `{"a": 1}`, pointer: "", value: []any{}, expected: `[]`},
+		{name: "escaped key", doc: `{}`, pointer: "/a~1b", value: 1.0, expected: `{"a/b": 1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Set(decode(t, tt.doc), tt.pointer, tt.value)
+			if err != nil {
+				t.Fatalf("Set() error = %v", err)
+			}
+			if want := decode(t, tt.expected); !reflect.DeepEqual(got, want) {
+				t.Errorf("Set() = %v, want %v", got, want)
+			}
+		})
+	}
+
+	for _, ptr := range []string{"/a/b/c", "/list/5", "/list/x", "/n/x", "a"} {
+		if _, err := Set(decode(t, `{"list": [], "n": 1}`), ptr, 1.0); err == nil {
+			t.Errorf("Set(%q) succeeded", ptr)
+		}
+	}
+}
+
+func TestAdd(t *testing.T) {
+	tests := []struct {
+		pointer  string
+		expected string
+	}{
+		{pointer: "/a/0", expected: `{"a": ["new", 1, 2]}`},
+		{pointer: "/a/1", expected: `{"a": [1, "new", 2]}`},
+		{pointer: "/a/2", expected: `{"a": [1, 2, "new"]}`},
+		{pointer: "/a/-", expected: `{"a": [1, 2, "new"]}`},
+		{pointer: "/b", expected: `{"a": [1, 2], "b": "new"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pointer, func(t *testing.T) {
+			p, _ := Parse(tt.pointer)
+			got, err := p.Add(decode(t, `{"a": [1, 2]}`), "new")
+			if err != nil {
+				t.Fatalf("Add() error = %v", err)
+			}
+			if want := decode(t, tt.expected); !reflect.DeepEqual(got, want) {
+				t.Errorf("Add() = %v, want %v", got, want)
+			}
+		})
+	}
+
+	_, err := New("a", "3").Add(decode(t, `{"a": [1, 2]}`), "new")
+	var notFound *NotFoundError
+	if !errors.As(err, &notFound) || notFound.Pointer != "/a/3" {
+		t.Errorf("Add() error = %v, want a NotFoundError at /a/3", err)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      string
+		pointer  string
+		expected string
+	}{
+		{name: "member", doc: `{"a": 1, "b": 2}`, pointer: "/a", expected: `{"b": 2}`},
+		{name: "first element", doc: `{"a": [1, 2, 3]}`, pointer: "/a/0", expected: `{"a": [2, 3]}`},
+		{name: "last element", doc: `[[1, 2, 3]]`, pointer: "/0/2", expected: `[[1, 2]]`},
+		{name: "root", doc: `[1]`, pointer: "", expected: `null`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Delete(decode(t, tt.doc), tt.pointer)
+			if err != nil {
+				t.Fatalf("Delete() error = %v", err)
+			}
+			if want := decode(t, tt.expected); !reflect.DeepEqual(got, want) {
+				t.Errorf("Delete() = %v, want %v", got, want)
+			}
+		})
+	}
+
+	for _, ptr := range []string{"/missing", "/a/3", "/a/-", "/b/c"} {
+		_, err := Delete(decode(t, `{"a": [1], "b": "s"}`), ptr)
+		var notFound *NotFoundError
+		if !errors.As(err, &notFound) {
+			t.Errorf("Delete(%q) error = %v, want a NotFoundError", ptr, err)
+		}
+	}
+}
+
+func TestOrderedObject(t *testing.T) {
+	var doc any
+	if err := parser.DecodeWithOptions(`{"b": {"x": [1]}, "a": 2}`, &doc, parser.UseOrderedObject()); err != nil {
+		t.Fatalf("DecodeWithOptions() error = %v", err)
+	}
+	if v, err := Get(doc, "/b/x/0"); err != nil || v != 1.0 {
+		t.Errorf("Get() = %v, %v", v, err)
+	}
+	doc, err := Set(doc, "/b/y", "new")
+	if err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if doc, err = Delete(doc, "/a"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	got, err := parser.Encode(doc)
+	if err != nil || got != `{"b":{"x":[1],"y":"new"}}` {
+		t.Errorf("Encode() = %s, %v", got, err)
+	}
+}
@@ -0,0 +1,113 @@
+package pointer
+
+import (
+	"io"
+	"strings"
+
+	"github.com/Ronit-Raj/json-parser/parser"
+)
+
+// GetRaw returns the source text of the value the pointer ptr names in the
+// JSON document text. See Pointer.GetRaw.
+func GetRaw(text, ptr string) (parser.RawMessage, error) {
+	p, err := Parse(ptr)
+	if err != nil {
+		return nil, err
+	}
+	return p.GetRaw(text)
+}
+
+// GetRaw returns the source text of the value p names in the JSON document
+// text, without decoding the document. It reads the text with a
+// parser.Tokenizer and skips every member and element off the path, checking
+// its syntax but building nothing, so looking up one value in a large
+// document costs little more than scanning up to it. Decode the result with
+// parser.Decode to get at the value.
+//
+// Everything before the value is checked, but reading stops at the end of
+// the value, so errors in the text after it go unnoticed. If an object
+// repeats a key, GetRaw follows the first one, as
+// parser.DuplicateKeys(parser.FirstKeyWins) would.
+func (p Pointer) GetRaw(text string) (parser.RawMessage, error) {
+	return p.getRaw(parser.NewTokenizer(strings.NewReader(text)))
+}
+
+func (p Pointer) getRaw(tok *parser.Tokenizer) (parser.RawMessage, error) {
+	for i, token := range p {
+		container, err := tok.Next()
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch container.Kind {
+		case parser.TokenBeginObject:
+			for {
+				if !tok.More() {
+					// A syntax error takes precedence over not finding the key.
+					if _, err := tok.Next(); err != nil {
+						return nil, err
+					}
+					return nil, p.notFound(i, "no member %q", token)
+				}
+				key, err := tok.Next()
+				if err != nil {
+					return nil, err
+				}
+				if key.Value == token {
+					break
+				}
+				if err := tok.Skip(); err != nil {
+					return nil, err
+				}
+			}
+		case parser.TokenBeginArray:
+			if token == "-" {
+				return nil, p.notFound(i, "no element after the end of the array")
+			}
+			index, ok := parseIndex(token)
+			if !ok {
+				return nil, p.notFound(i, "invalid array index %q", token)
+			}
+			for n := 0; n <= index; n++ {
+				if !tok.More() {
+					if _, err := tok.Next(); err != nil {
+						return nil, err
+					}
+					return nil, p.notFound(i, "index %s out of range for array of length %d", token, n)
+				}
+				if n < index {
+					if err := tok.Skip(); err != nil {
+						return nil, err
+					}
+				}
+			}
+		default:
+			return nil, p.notFound(i, "cannot look up %q in %s", token, tokenKind(container.Kind))
+		}
+	}
+
+	var raw parser.RawMessage
+	if err := tok.Decode(&raw); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return raw, nil
+}
+
+// tokenKind describes the JSON kind of a scalar token for error messages.
+func tokenKind(kind parser.TokenKind) string {
+	switch kind {
+	case parser.TokenString:
+		return "string"
+	case parser.TokenNumber:
+		return "number"
+	case parser.TokenBool:
+		return "boolean"
+	}
+	return "null"
+}
@@ -0,0 +1,116 @@
+package pointer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Ronit-Raj/json-parser/scanner"
+)
+
+func TestGetRaw(t *testing.T) {
+	tests := []struct {
+		pointer  string
+		expected string
+	}{
+		{pointer: "", expected: rfcDocument},
+		{pointer: "/foo", expected: `["bar", "baz"]`},
+		{pointer: "/foo/1", expected: `"baz"`},
+		{pointer: "/", expected: `0`},
+		{pointer: "/a~1b", expected: `1`},
+		{pointer: "/i\\j", expected: `5`},
+		{pointer: "/k\"l", expected: `6`},
+		{pointer: "/m~0n", expected: `8`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pointer, func(t *testing.T) {
+			got, err := GetRaw(rfcDocument, tt.pointer)
+			if err != nil {
+				t.Fatalf("GetRaw() error = %v", err)
+			}
+			if string(got) != tt.expected {
+				t.Errorf("GetRaw() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetRawMatchesGet(t *testing.T) {
+	text := `{"users": [{"name": "Ada", "langs": ["en", "fr"]}, {"name": "Lin", "id": 2}], "meta": {"n": null}}`
+	doc := decode(t, text)
+	for _, ptr := range []string{"/users/1/id", "/users/0/langs/1", "/meta", "/meta/n", "/users/1"} {
+		raw, err := GetRaw(text, ptr)
+		if err != nil {
+			t.Fatalf("GetRaw(%q) error = %v", ptr, err)
+		}
+		want, _ := Get(doc, ptr)
+		if got := decode(t, string(raw)); fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("GetRaw(%q) = %s, want %v", ptr, raw, want)
+		}
+	}
+}
+
+func TestGetRawErrors(t *testing.T) {
+	text := `{"users": [{"name": "Ada"}], "count": 1}`
+	for _, ptr := range []string{"/missing", "/users/1", "/users/-", "/users/01", "/count/0", "/users/0/name/x"} {
+		_, err := GetRaw(text, ptr)
+		var notFound *NotFoundError
+		if !errors.As(err, &notFound) {
+			t.Errorf("GetRaw(%q) error = %v, want a NotFoundError", ptr, err)
+		}
+	}
+
+	// Skipped values are still checked.
+	for _, text := range []string{`{"a": [1 2], "b": 1}`, `{"a": 1`, `{"a": [1, 2`, `{"a" 1, "b": 2}`} {
+		_, err := GetRaw(text, "/b")
+		var syntaxErr scanner.SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("GetRaw(%s) error = %v, want a SyntaxError", text, err)
+		}
+	}
+
+	// So are the commas up to the value.
+	for _, tt := range []struct{ text, ptr string }{
+		{text: `[1 2 3]`, ptr: "/2"},
+		{text: `[1, 2 3]`, ptr: "/2"},
+		{text: `{"a": 1 "b": 2}`, ptr: "/b"},
+		{text: `{"a": [1 2 3]}`, ptr: "/a/1"},
+	} {
+		_, err := GetRaw(tt.text, tt.ptr)
+		var syntaxErr scanner.SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("GetRaw(%s, %q) error = %v, want a SyntaxError", tt.text, tt.ptr, err)
+		}
+	}
+
+	// Only the text up to the end of the value is read.
+	if raw, err := GetRaw(`{"a": 1, "b": [`, "/a"); err != nil || string(raw) != `1` {
+		t.Errorf("GetRaw() = %s, %v", raw, err)
+	}
+}
+
+func TestGetRawLargeDocument(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString(`{"skip": [`)
+	for i := 0; i < 10000; i++ {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(&sb, `{"id": %d, "tags": ["a", "b"]}`, i)
+	}
+	sb.WriteString(`], "items": [`)
+	for i := 0; i < 1000; i++ {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(&sb, `"item %d"`, i)
+	}
+	sb.WriteString(`]}`)
+
+	raw, err := GetRaw(sb.String(), "/items/999")
+	if err != nil || string(raw) != `"item 999"` {
+		t.Errorf("GetRaw() = %s, %v", raw, err)
+	}
+}
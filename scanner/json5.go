@@ -0,0 +1,308 @@
+package scanner
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// lineContinuation is what readEscape returns for a backslash before a line
+// break in a JSON5 string, which stands for nothing.
+const lineContinuation rune = -1
+
+// skipJSON5Space skips white space and comments. Besides the white space of
+// JSON, JSON5 allows \v, \f, non-breaking spaces, the byte order mark, the
+// line and paragraph separators and any other Unicode space.
+func (s *Scanner) skipJSON5Space() error {
+	for s.pointer < len(s.text) {
+		rest := s.text[s.pointer:]
+		switch {
+		case strings.HasPrefix(rest, "//"):
+			end := strings.IndexAny(rest, "\n\r\u2028\u2029")
+			if end < 0 {
+				// The comment may go on in input not read yet.
+				s.pointer = len(s.text)
+				s.hitEnd = true
+				return nil
+			}
+			s.pointer += end
+		case strings.HasPrefix(rest, "/*"):
+			end := strings.Index(rest[2:], "*/")
+			if end < 0 {
+				start := s.pointer
+				s.pointer = len(s.text)
+				s.hitEnd = true
+				return newSyntaxError(start, "unterminated comment")
+			}
+			s.pointer += 2 + end + 2
+		case rest == "/":
+			// Maybe the start of a comment; let scanToken decide.
+			s.hitEnd = true
+			return nil
+		default:
+			if !utf8.FullRuneInString(rest) {
+				// Maybe white space split by the end of the window.
+				s.hitEnd = true
+				return nil
+			}
+			r, size := utf8.DecodeRuneInString(rest)
+			if !isJSON5Space(r) {
+				return nil
+			}
+			s.pointer += size
+		}
+	}
+	s.hitEnd = true
+	return nil
+}
+
+func isJSON5Space(r rune) bool {
+	switch r {
+	case ' ', '\t', '\n', '\r', '\v', '\f', '\u00a0', '\ufeff', '\u2028', '\u2029':
+		return true
+	}
+	return unicode.Is(unicode.Zs, r)
+}
+
+// startsJSON5Token reports whether a token starting with c is scanned
+// differently in JSON5: single-quoted strings, identifiers and numbers.
+// Identifiers include true, false and null, since in JSON5 truely is a
+// valid key rather than true followed by garbage.
+func startsJSON5Token(c rune) bool {
+	return c == '\'' || c == '+' || c == '-' || c == '.' || isDigit(c) || c == '\\' || isIdentifierStart(c)
+}
+
+// scanJSON5Token scans a token for which startsJSON5Token holds.
+func (s *Scanner) scanJSON5Token(c rune) (Token, error) {
+	switch {
+	case c == '\'':
+		s.pointer++
+		stringVal, err := s.readString('\'')
+		return Token{NumVal: math.NaN(), StringVal: stringVal, TypeOfToken: STRING}, err
+	case c == '\\' || isIdentifierStart(c):
+		return s.readIdentifier()
+	}
+	numVal, err := s.readJSON5Number()
+	return Token{NumVal: numVal, TypeOfToken: NUMBER}, err
+}
+
+// isIdentifierStart reports whether an ECMAScript identifier may start with
+// c: a Unicode letter, '$' or '_'.
+func isIdentifierStart(c rune) bool {
+	return c == '$' || c == '_' || unicode.IsLetter(c) || unicode.Is(unicode.Nl, c)
+}
+
+// isIdentifierPart reports whether c may continue an ECMAScript identifier.
+func isIdentifierPart(c rune) bool {
+	return isIdentifierStart(c) || unicode.In(c, unicode.Mn, unicode.Mc, unicode.Nd, unicode.Pc) ||
+		c == '\u200c' || c == '\u200d'
+}
+
+// readIdentifier reads an identifier, in which characters may be written as
+// \u escapes. The names true, false and null give the literals, and Infinity
+// and NaN give numbers.
+func (s *Scanner) readIdentifier() (Token, error) {
+	start := s.pointer
+	var sb strings.Builder
+	for s.pointer < len(s.text) {
+		if !utf8.FullRuneInString(s.text[s.pointer:]) {
+			s.hitEnd = true
+		}
+		r, size := utf8.DecodeRuneInString(s.text[s.pointer:])
+		if r == '\\' {
+			if s.pointer+1 >= len(s.text) {
+				s.hitEnd = true
+			}
+			if s.pointer+1 >= len(s.text) || s.text[s.pointer+1] != 'u' {
+				return Token{}, newSyntaxError(s.pointer, "invalid escape in identifier, expected \\u")
+			}
+			var ok bool
+			if r, ok = s.readHex4(s.pointer + 2); !ok {
+				return Token{}, newSyntaxError(s.pointer, "invalid \\u escape, expected four hex digits")
+			}
+			size = 6
+		}
+		valid := isIdentifierPart(r)
+		if s.pointer == start {
+			valid = isIdentifierStart(r)
+		}
+		if !valid {
+			if s.pointer == start {
+				return Token{}, newSyntaxError(s.pointer, "invalid %s at start of identifier", describe(r))
+			}
+			if s.text[s.pointer] == '\\' {
+				return Token{}, newSyntaxError(s.pointer, "invalid %s in identifier", describe(r))
+			}
+			break
+		}
+		sb.WriteRune(r)
+		s.pointer += size
+	}
+	if s.pointer == len(s.text) {
+		s.hitEnd = true
+	}
+
+	switch raw := s.text[start:s.pointer]; raw {
+	case "true":
+		return Token{NumVal: math.NaN(), TypeOfToken: LITERAL_TRUE}, nil
+	case "false":
+		return Token{NumVal: math.NaN(), TypeOfToken: LITERAL_FALSE}, nil
+	case "null":
+		return Token{NumVal: math.NaN(), TypeOfToken: LITERAL_NULL}, nil
+	case "Infinity":
+		return Token{NumVal: math.Inf(1), TypeOfToken: NUMBER}, nil
+	case "NaN":
+		return Token{NumVal: math.NaN(), TypeOfToken: NUMBER}, nil
+	}
+	return Token{NumVal: math.NaN(), StringVal: sb.String(), TypeOfToken: IDENTIFIER}, nil
+}
+
+// readJSON5Number reads a number with an optional sign: Infinity, NaN, a
+// hexadecimal integer, or a decimal number whose integer or fractional part
+// may be missing, but not both.
+func (s *Scanner) readJSON5Number() (float64, error) {
+	start := s.pointer
+	sign := 1.0
+	if c := s.text[s.pointer]; c == '+' || c == '-' {
+		if c == '-' {
+			sign = -1
+		}
+		s.pointer++
+	}
+	if s.pointer == len(s.text) {
+		return math.NaN(), s.numberError()
+	}
+
+	switch rest := s.text[s.pointer:]; {
+	case rest[0] == 'I':
+		if !s.match("Infinity") {
+			return math.NaN(), s.numberError()
+		}
+		return math.Inf(int(sign)), nil
+	case rest[0] == 'N':
+		if !s.match("NaN") {
+			return math.NaN(), s.numberError()
+		}
+		return math.NaN(), nil
+	case len(rest) >= 2 && rest[0] == '0' && (rest[1] == 'x' || rest[1] == 'X'):
+		s.pointer += 2
+		digits := s.pointer
+		for s.pointer < len(s.text) && isHexDigit(s.text[s.pointer]) {
+			s.pointer++
+		}
+		if s.pointer == len(s.text) {
+			s.hitEnd = true
+		}
+		if s.pointer == digits {
+			return math.NaN(), s.numberError()
+		}
+		n, _ := new(big.Int).SetString(s.text[digits:s.pointer], 16)
+		f, _ := new(big.Float).SetInt(n).Float64()
+		return sign * f, nil
+	}
+
+	// As in JSON, an integer part starting with 0 is just the 0.
+	intDigits := 0
+	if s.text[s.pointer] == '0' {
+		s.pointer++
+		intDigits = 1
+	} else {
+		intDigits = s.digits()
+	}
+	fracDigits := 0
+	if s.pointer < len(s.text) && s.text[s.pointer] == '.' {
+		s.pointer++
+		fracDigits = s.digits()
+	}
+	if intDigits == 0 && fracDigits == 0 {
+		return math.NaN(), s.numberError()
+	}
+	if s.pointer < len(s.text) && (s.text[s.pointer] == 'e' || s.text[s.pointer] == 'E') {
+		s.pointer++
+		if s.pointer < len(s.text) && (s.text[s.pointer] == '+' || s.text[s.pointer] == '-') {
+			s.pointer++
+		}
+		if s.digits() == 0 {
+			return math.NaN(), s.numberError()
+		}
+	}
+	if s.pointer == len(s.text) {
+		s.hitEnd = true
+	}
+	num, _ := strconv.ParseFloat(s.text[start:s.pointer], 64)
+	return num, nil
+}
+
+// digits skips ASCII digits and returns how many there were.
+func (s *Scanner) digits() int {
+	start := s.pointer
+	for s.pointer < len(s.text) && isDigit(rune(s.text[s.pointer])) {
+		s.pointer++
+	}
+	return s.pointer - start
+}
+
+// numberError reports the character at which a number went wrong.
+func (s *Scanner) numberError() error {
+	if s.pointer >= len(s.text) {
+		s.hitEnd = true
+		return newSyntaxError(s.pointer, "unexpected end of input in number")
+	}
+	c, _ := utf8.DecodeRuneInString(s.text[s.pointer:])
+	return newSyntaxError(s.pointer, "invalid %s in number", describe(c))
+}
+
+func isHexDigit(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+// readJSON5Escape decodes the escape sequences JSON5 adds to those of JSON,
+// starting with the backslash at offset i.
+func (s *Scanner) readJSON5Escape(i int) (rune, int, error) {
+	switch c := s.text[i+1]; c {
+	case '\'':
+		return '\'', 2, nil
+	case 'v':
+		return '\v', 2, nil
+	case '0':
+		if i+2 < len(s.text) && isDigit(rune(s.text[i+2])) {
+			return 0, 0, newSyntaxError(i, "invalid escape sequence \\0 followed by a digit")
+		}
+		if i+2 >= len(s.text) {
+			s.hitEnd = true
+		}
+		return 0, 2, nil
+	case 'x':
+		if i+4 > len(s.text) {
+			s.hitEnd = true
+		}
+		if i+4 > len(s.text) || !isHexDigit(s.text[i+2]) || !isHexDigit(s.text[i+3]) {
+			return 0, 0, newSyntaxError(i, "invalid \\x escape, expected two hex digits")
+		}
+		n, _ := strconv.ParseUint(s.text[i+2:i+4], 16, 8)
+		return rune(n), 4, nil
+	case '\n':
+		return lineContinuation, 2, nil
+	case '\r':
+		if i+2 >= len(s.text) {
+			s.hitEnd = true
+		} else if s.text[i+2] == '\n' {
+			return lineContinuation, 3, nil
+		}
+		return lineContinuation, 2, nil
+	case '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return 0, 0, newSyntaxError(i, "invalid escape sequence \\%c", c)
+	}
+	if !utf8.FullRuneInString(s.text[i+1:]) {
+		s.hitEnd = true
+	}
+	r, size := utf8.DecodeRuneInString(s.text[i+1:])
+	if r == '\u2028' || r == '\u2029' {
+		return lineContinuation, 1 + size, nil
+	}
+	// Any other character stands for itself.
+	return r, 1 + size, nil
+}
@@ -0,0 +1,155 @@
+package scanner
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// newJSON5 returns a Scanner for text in JSON5 mode.
+func newJSON5(text string) *Scanner {
+	s := New(text)
+	s.SetJSON5(true)
+	return s
+}
+
+func TestJSON5Tokens(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []Token
+	}{
+		{name: "line comment", input: "// note\n1 // trailing",
+			expected: []Token{{TypeOfToken: NUMBER, NumVal: 1, Raw: "1"}}},
+		{name: "block comment", input: "[/* a */1/**/,/* * / */2]",
+			expected: []Token{{TypeOfToken: BEGIN_ARRAY, Raw: "["}, {TypeOfToken: NUMBER, NumVal: 1, Raw: "1"},
+				{TypeOfToken: VALUE_SEPARATOR, Raw: ","}, {TypeOfToken: NUMBER, NumVal: 2, Raw: "2"}, {TypeOfToken: END_ARRAY, Raw: "]"}}},
+		{name: "unicode white space", input: "\ufeff\v\f\u00a0\u2028\u3000true",
+			expected: []Token{{TypeOfToken: LITERAL_TRUE, Raw: "true"}}},
+		{name: "single quotes", input: `'say "hi"'`,
+			expected: []Token{{TypeOfToken: STRING, StringVal: `say "hi"`, Raw: `'say "hi"'`}}},
+		{name: "JSON5 escapes", input: `'\'\v\0\x41\aé'`,
+			expected: []Token{{TypeOfToken: STRING, StringVal: "'\v\x00Aaé", Raw: `'\'\v\0\x41\aé'`}}},
+		{name: "line continuation", input: "\"a\\\nb\\\r\nc\\\u2028d\"",
+			expected: []Token{{TypeOfToken: STRING, StringVal: "abcd", Raw: "\"a\\\nb\\\r\nc\\\u2028d\""}}},
+		{name: "tab in string", input: "'a\tb'",
+			expected: []Token{{TypeOfToken: STRING, StringVal: "a\tb", Raw: "'a\tb'"}}},
+		{name: "identifiers", input: `$id _x café ab truely`,
+			expected: []Token{{TypeOfToken: IDENTIFIER, StringVal: "$id", Raw: "$id"}, {TypeOfToken: IDENTIFIER, StringVal: "_x", Raw: "_x"},
+				{TypeOfToken: IDENTIFIER, StringVal: "café", Raw: "café"}, {TypeOfToken: IDENTIFIER, StringVal: "ab", Raw: `ab`},
+				{TypeOfToken: IDENTIFIER, StringVal: "truely", Raw: "truely"}}},
+		{name: "literals", input: `null false true`,
+			expected: []Token{{TypeOfToken: LITERAL_NULL, Raw: "null"}, {TypeOfToken: LITERAL_FALSE, Raw: "false"}, {TypeOfToken: LITERAL_TRUE, Raw: "true"}}},
+		{name: "numbers", input: `0x1F -0XfF .5 5. +1 -2.5e+3 0 1e3`,
+			expected: []Token{{TypeOfToken: NUMBER, NumVal: 31, Raw: "0x1F"}, {TypeOfToken: NUMBER, NumVal: -255, Raw: "-0XfF"},
+				{TypeOfToken: NUMBER, NumVal: 0.5, Raw: ".5"}, {TypeOfToken: NUMBER, NumVal: 5, Raw: "5."},
+				{TypeOfToken: NUMBER, NumVal: 1, Raw: "+1"}, {TypeOfToken: NUMBER, NumVal: -2500, Raw: "-2.5e+3"},
+				{TypeOfToken: NUMBER, NumVal: 0, Raw: "0"}, {TypeOfToken: NUMBER, NumVal: 1000, Raw: "1e3"}}},
+		{name: "infinity and NaN", input: `Infinity -Infinity +NaN`,
+			expected: []Token{{TypeOfToken: NUMBER, NumVal: math.Inf(1), Raw: "Infinity"},
+				{TypeOfToken: NUMBER, NumVal: math.Inf(-1), Raw: "-Infinity"}, {TypeOfToken: NUMBER, NumVal: math.NaN(), Raw: "+NaN"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := collectTokens(newJSON5(tt.input))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			want := append(tt.expected, Token{TypeOfToken: EOF})
+			if len(got) != len(want) {
+				t.Fatalf("expected %d tokens, got %+v", len(want), got)
+			}
+			for i := range want {
+				g, w := got[i], want[i]
+				sameNumber := g.NumVal == w.NumVal || math.IsNaN(g.NumVal) && math.IsNaN(w.NumVal)
+				if g.TypeOfToken != w.TypeOfToken || g.StringVal != w.StringVal || g.Raw != w.Raw ||
+					(w.TypeOfToken == NUMBER && !sameNumber) {
+					t.Errorf("token %d: expected %+v, got %+v", i, w, g)
+				}
+			}
+		})
+	}
+}
+
+func TestJSON5Errors(t *testing.T) {
+	tests := []struct {
+		input    string
+		position int
+		message  string
+	}{
+		{input: `/* open`, position: 0, message: "unterminated comment"},
+		{input: `/ x`, position: 0, message: "invalid character '/'"},
+		{input: "'a\nb'", position: 2, message: "invalid control character U+000A"},
+		{input: `'\1'`, position: 1, message: `invalid escape sequence \1`},
+		{input: `'\01'`, position: 1, message: `\0 followed by a digit`},
+		{input: `'\xG0'`, position: 1, message: `invalid \x escape`},
+		{input: `'abc`, position: 1, message: "unterminated string"},
+		{input: `a\x`, position: 1, message: `expected \u`},
+		{input: `a\u0020`, position: 1, message: "invalid character ' ' in identifier"},
+		{input: `\u0031`, position: 0, message: "invalid character '1' at start of identifier"},
+		{input: `+`, position: 1, message: "unexpected end of input in number"},
+		{input: `-.`, position: 2, message: "unexpected end of input in number"},
+		{input: `.e1`, position: 1, message: "invalid character 'e' in number"},
+		{input: `0x`, position: 2, message: "unexpected end of input in number"},
+		{input: `0xg`, position: 2, message: "invalid character 'g' in number"},
+		{input: `1e+`, position: 3, message: "unexpected end of input in number"},
+		{input: `+Infinite`, position: 8, message: "invalid character 'e' in number"},
+		{input: `-nan`, position: 1, message: "invalid character 'n' in number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := collectTokens(newJSON5(tt.input))
+			syntaxErr, ok := err.(SyntaxError)
+			if !ok {
+				t.Fatalf("expected SyntaxError, got %v", err)
+			}
+			if syntaxErr.Position != tt.position || !strings.Contains(syntaxErr.Msg, tt.message) {
+				t.Errorf("got %q at %d, want %q at %d", syntaxErr.Msg, syntaxErr.Position, tt.message, tt.position)
+			}
+		})
+	}
+}
+
+func TestJSON5IsOptIn(t *testing.T) {
+	for _, input := range []string{`// c`, `/* c */`, `'x'`, `key`, `+1`, `.5`, `0x1`, `Infinity`, `NaN`, "\u00a01"} {
+		if _, err := collectTokens(New(input)); err == nil {
+			t.Errorf("strict scanner accepted %q", input)
+		}
+	}
+	if _, err := collectTokens(New(`"\'"`)); err == nil {
+		t.Errorf(`strict scanner accepted \'`)
+	}
+}
+
+func TestJSON5Reader(t *testing.T) {
+	input := "{\n  // comment\n  unquoted: 'single', /* block\n comment */ hex: 0xCAFE,\n  list: [.5, +Infinity, 5.,],\n" +
+		"  esc: 'a\\\nb\\x41\\u00e9', été: NaN, \u2028 trailing: 1e-3,\n}"
+	want, err := collectTokens(newJSON5(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for name, r := range map[string]*Scanner{
+		"one byte": NewReader(iotest.OneByteReader(strings.NewReader(input))),
+		"half":     NewReader(iotest.HalfReader(strings.NewReader(input))),
+	} {
+		t.Run(name, func(t *testing.T) {
+			r.SetJSON5(true)
+			got, err := collectTokens(r)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(want) {
+				t.Fatalf("expected %d tokens, got %d", len(want), len(got))
+			}
+			for i := range want {
+				if got[i].TypeOfToken != want[i].TypeOfToken || got[i].Raw != want[i].Raw ||
+					got[i].StringVal != want[i].StringVal || got[i].Start != want[i].Start {
+					t.Errorf("token %d: expected %+v, got %+v", i, want[i], got[i])
+				}
+			}
+		})
+	}
+}
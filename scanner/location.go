@@ -0,0 +1,91 @@
+package scanner
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Location pinpoints a place in the input. It is embedded in every error
+// that refers to the input, so callers can report it however they like.
+type Location struct {
+	Position int    // byte offset, starting at 0
+	Line     int    // line number, starting at 1; 0 if unknown
+	Column   int    // column in runes, starting at 1; 0 if unknown
+	Excerpt  string // the source line and a caret under Column
+}
+
+func (l Location) String() string {
+	if l.Line == 0 {
+		return fmt.Sprintf("offset %d", l.Position)
+	}
+	return fmt.Sprintf("%d:%d", l.Line, l.Column)
+}
+
+// maxExcerpt is the widest source excerpt, in runes, an error carries.
+const maxExcerpt = 60
+
+// Locate returns the Location of the byte offset. A Scanner reading from an
+// io.Reader can only locate offsets that are still in its window; for
+// earlier offsets only Position is set.
+func (s *Scanner) Locate(offset int) Location {
+	loc := Location{Position: offset}
+	rel := offset - s.base
+	if rel < 0 || rel > len(s.text) {
+		return loc
+	}
+
+	before := s.text[:rel]
+	line, col, lineStart := s.baseLine, s.baseCol, 0
+	if i := strings.LastIndexByte(before, '\n'); i >= 0 {
+		line += strings.Count(before, "\n")
+		col, lineStart = 0, i+1
+	}
+	caret := utf8.RuneCountInString(before[lineStart:])
+	loc.Line, loc.Column = line+1, col+caret+1
+
+	lineEnd := len(s.text)
+	if i := strings.IndexByte(s.text[rel:], '\n'); i >= 0 {
+		lineEnd = rel + i
+	}
+	loc.Excerpt = excerpt(strings.TrimSuffix(s.text[lineStart:lineEnd], "\r"), caret)
+	return loc
+}
+
+// advanceLocation records the line and column of the new window start when
+// the first n bytes of the window are discarded.
+func (s *Scanner) advanceLocation(n int) {
+	discarded := s.text[:n]
+	if i := strings.LastIndexByte(discarded, '\n'); i >= 0 {
+		s.baseLine += strings.Count(discarded, "\n")
+		s.baseCol = utf8.RuneCountInString(discarded[i+1:])
+		return
+	}
+	s.baseCol += utf8.RuneCountInString(discarded)
+}
+
+// excerpt renders line with a caret under the rune at index caret, cutting
+// the line down to maxExcerpt runes around the caret.
+func excerpt(line string, caret int) string {
+	runes := []rune(line)
+	start, end := 0, len(runes)
+	if end > maxExcerpt {
+		start = max(0, caret-maxExcerpt/2)
+		end = min(len(runes), start+maxExcerpt)
+		start = max(0, end-maxExcerpt)
+	}
+	prefix, suffix := "", ""
+	if start > 0 {
+		prefix = "..."
+	}
+	if end < len(runes) {
+		suffix = "..."
+	}
+	for i := start; i < end; i++ {
+		if runes[i] == '\t' {
+			runes[i] = ' '
+		}
+	}
+	pad := len([]rune(prefix)) + caret - start
+	return prefix + string(runes[start:end]) + suffix + "\n" + strings.Repeat(" ", pad) + "^"
+}
@@ -0,0 +1,100 @@
+package scanner
+
+import (
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestLocate(t *testing.T) {
+	input := "{\n\t\"név\": [1,\r\n  @]\n}"
+	tests := []struct {
+		name    string
+		offset  int
+		line    int
+		column  int
+		excerpt string
+	}{
+		{name: "start", offset: 0, line: 1, column: 1, excerpt: "{\n^"},
+		{name: "tab indented", offset: 3, line: 2, column: 2, excerpt: " \"név\": [1,\n ^"},
+		{name: "after multibyte rune", offset: 8, line: 2, column: 6, excerpt: " \"név\": [1,\n     ^"},
+		{name: "after CRLF", offset: 18, line: 3, column: 3, excerpt: "  @]\n  ^"},
+		{name: "end of input", offset: len(input), line: 4, column: 2, excerpt: "}\n ^"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc := New(input).Locate(tt.offset)
+			if loc.Position != tt.offset || loc.Line != tt.line || loc.Column != tt.column {
+				t.Errorf("expected %d at %d:%d, got %d at %d:%d", tt.offset, tt.line, tt.column, loc.Position, loc.Line, loc.Column)
+			}
+			if loc.Excerpt != tt.excerpt {
+				t.Errorf("expected excerpt\n%s\ngot\n%s", tt.excerpt, loc.Excerpt)
+			}
+		})
+	}
+}
+
+func TestLocateLongLine(t *testing.T) {
+	input := strings.Repeat("a", 100) + "@" + strings.Repeat("b", 100)
+	loc := New(input).Locate(100)
+	lines := strings.Split(loc.Excerpt, "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected two excerpt lines, got %q", loc.Excerpt)
+	}
+	caret := strings.Index(lines[1], "^")
+	if caret < 0 || lines[0][caret] != '@' {
+		t.Errorf("caret does not point at the offending character:\n%s", loc.Excerpt)
+	}
+	if !strings.HasPrefix(lines[0], "...") || !strings.HasSuffix(lines[0], "...") {
+		t.Errorf("expected a trimmed excerpt, got %q", lines[0])
+	}
+	if loc.Column != 101 {
+		t.Errorf("expected column 101, got %d", loc.Column)
+	}
+}
+
+func TestSyntaxErrorLocation(t *testing.T) {
+	input := "[\n  \"ok\",\n  \"tab\there\"\n]"
+	for name, s := range map[string]*Scanner{
+		"text":   New(input),
+		"reader": NewReader(iotest.OneByteReader(strings.NewReader(input))),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := collectTokens(s)
+			syntaxErr, ok := err.(SyntaxError)
+			if !ok {
+				t.Fatalf("expected SyntaxError, got %v", err)
+			}
+			if syntaxErr.Position != 16 || syntaxErr.Line != 3 || syntaxErr.Column != 7 {
+				t.Errorf("expected error at 16 (3:7), got %d (%d:%d)", syntaxErr.Position, syntaxErr.Line, syntaxErr.Column)
+			}
+			if want := "3:7: invalid control character U+0009 in string"; syntaxErr.Error() != want {
+				t.Errorf("expected %q, got %q", want, syntaxErr.Error())
+			}
+		})
+	}
+}
+
+func TestLocateAcrossRefills(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("[")
+	for i := 0; i < 2000; i++ {
+		sb.WriteString("\n  \"é\",")
+	}
+	sb.WriteString("\n  @]")
+	input := sb.String()
+
+	_, err := collectTokens(NewReader(strings.NewReader(input)))
+	syntaxErr, ok := err.(SyntaxError)
+	if !ok {
+		t.Fatalf("expected SyntaxError, got %v", err)
+	}
+	want := New(input).Locate(strings.Index(input, "@"))
+	if syntaxErr.Location != want {
+		t.Errorf("expected %+v, got %+v", want, syntaxErr.Location)
+	}
+	if syntaxErr.Line != 2002 || syntaxErr.Column != 3 {
+		t.Errorf("expected 2002:3, got %d:%d", syntaxErr.Line, syntaxErr.Column)
+	}
+}
@@ -1,11 +1,16 @@
 package scanner
 
 import (
-	"unicode"
-	"unicode/utf8"
+	"bytes"
 	"fmt"
+	"io"
 	"math"
+	"slices"
 	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
 )
 
 type TokenType uint8
@@ -23,248 +28,616 @@ const LITERAL_TRUE TokenType = 10
 const LITERAL_NULL TokenType = 11
 const EOF TokenType = 12
 
+// IDENTIFIER is an unquoted object key, which only JSON5 allows. Its name is
+// in StringVal.
+const IDENTIFIER TokenType = 13
+
 type Token struct {
 	NumVal      float64
 	StringVal   string
 	TypeOfToken TokenType
+	// Raw is the token exactly as it appears in the input, and Start and End
+	// are the byte offsets of its first character and of the character after
+	// its last one.
+	Raw        string
+	Start, End int
 }
+
+// SyntaxError reports malformed input. The embedded Location says where.
 type SyntaxError struct {
-	Msg  string
-	Position int
+	Msg string
+	Location
 }
 
 func (e SyntaxError) Error() string {
-	return fmt.Sprintf("Error:%d %s",e.Position,e.Msg)
+	return fmt.Sprintf("%v: %s", e.Location, e.Msg)
 }
 
-var Text string
-var pointer int
+func newSyntaxError(position int, format string, args ...any) SyntaxError {
+	return SyntaxError{Msg: fmt.Sprintf(format, args...), Location: Location{Position: position}}
+}
 
-func ResetPointer() {
-	pointer = 0
+// describe quotes the character c for an error message.
+func describe(c rune) string {
+	if c == utf8.RuneError {
+		return "invalid UTF-8"
+	}
+	return fmt.Sprintf("character %q", c)
+}
+
+// Scanner splits a JSON document into tokens. Every Scanner owns its input
+// and cursor, so separate Scanners can be used from separate goroutines.
+//
+// A Scanner reading from an io.Reader only holds a window of the input: text
+// starts at byte offset base of the whole input and is refilled as tokens are
+// consumed, so memory stays bounded by the largest single token.
+type Scanner struct {
+	text    string
+	pointer int
+	base    int
+
+	// baseLine and baseCol are the 0-based line and rune column of base.
+	baseLine int
+	baseCol  int
+
+	src    io.Reader
+	srcErr error // first error returned by src, usually io.EOF
+	hitEnd bool  // the last scan needed bytes past the end of text
+
+	json5 bool // accept JSON5 as well as JSON, see SetJSON5
+
+	// held are the absolute offsets passed to Hold and not yet released;
+	// fill keeps everything from the first of them in the window.
+	held []int
+}
+
+// minRead is the smallest number of bytes a Scanner asks its reader for.
+const minRead = 4096
+
+// New returns a Scanner positioned at the start of text.
+func New(text string) *Scanner {
+	return &Scanner{text: text}
+}
+
+// NewReader returns a Scanner that reads its input from r as needed.
+func NewReader(r io.Reader) *Scanner {
+	return &Scanner{src: r}
+}
+
+// SetJSON5 switches the scanner between strict RFC 8259 JSON, the default,
+// and JSON5, which also allows:
+//
+//   - // and /* */ comments wherever white space may appear,
+//   - strings in single quotes, with the escapes \', \v, \0 and \xHH, a
+//     backslash before a line break to continue the string on the next line,
+//     and any other character escaping to itself,
+//   - identifiers, such as name or $id, which the parser accepts as keys,
+//   - numbers with a '+' sign, in hexadecimal such as 0xFF, with a leading or
+//     trailing decimal point such as .5 or 5., and Infinity and NaN.
+//
+// Trailing commas in arrays and objects are up to the parser.
+func (s *Scanner) SetJSON5(enabled bool) {
+	s.json5 = enabled
+}
+
+// Offset returns the byte offset of the next unread character.
+func (s *Scanner) Offset() int {
+	return s.base + s.pointer
+}
+
+// Hold keeps the input from the absolute offset onwards in the window until
+// Release is called, so that Slice and Rewind can still reach it after the
+// scanner has read more input. Holds nest: each Release undoes the most
+// recent Hold, and offsets must not decrease while held.
+func (s *Scanner) Hold(offset int) {
+	s.held = append(s.held, offset)
 }
 
-func skipWhiteSpaces() {
-	currChar, size := utf8.DecodeRuneInString(Text[pointer:])
-	for currChar==' ' || currChar=='\n' || currChar=='\t'{
-		pointer+=size
-		currChar,size = utf8.DecodeRuneInString(Text[pointer:])
+// Release undoes the most recent Hold.
+func (s *Scanner) Release() {
+	s.held = s.held[:len(s.held)-1]
+}
+
+// Rewind moves the scanner back to the absolute offset, which must still be
+// in the window, so that the input from there is scanned again.
+func (s *Scanner) Rewind(offset int) {
+	s.pointer = offset - s.base
+}
+
+// Slice returns the input between the absolute offsets start and end. The
+// input must still be in the window: a Scanner reading from an io.Reader
+// only guarantees that for input after the last token and input kept by
+// Hold.
+func (s *Scanner) Slice(start, end int) string {
+	return s.text[start-s.base : end-s.base]
+}
+
+// fill discards the consumed part of the window and appends more input from
+// the reader. The bytes from pointer onwards, and any held bytes, are kept.
+//
+// The bytes from pointer onwards are the start of a token that NextToken
+// scans again from the beginning, which costs as much as their length. So
+// fill keeps reading until the new input might end the token or is as long
+// as what was there, and a large token takes linear time however little
+// each Read returns.
+func (s *Scanner) fill() {
+	discard := s.pointer
+	if len(s.held) > 0 {
+		discard = min(discard, s.held[0]-s.base)
+	}
+	s.advanceLocation(discard)
+	s.base += discard
+	s.text = s.text[discard:]
+	s.pointer -= discard
+
+	partial := s.text[s.pointer:]
+	buf := make([]byte, len(s.text), max(minRead, 2*len(s.text)))
+	copy(buf, s.text)
+	for {
+		if len(buf) == cap(buf) {
+			buf = slices.Grow(buf, len(buf))
+		}
+		n, err := s.src.Read(buf[len(buf):cap(buf)])
+		added := buf[len(buf) : len(buf)+n]
+		buf = buf[:len(buf)+n]
+		if err != nil {
+			s.srcErr = err
+			break
+		}
+		if n > 0 && (len(buf)-len(s.text) >= len(partial) || mayEnd(partial, added)) {
+			break
+		}
+	}
+	s.text = string(buf)
+}
+
+// mayEnd reports whether the input added after partial, the start of a
+// token, could hold the end of the token. It errs on the side of true.
+func mayEnd(partial string, added []byte) bool {
+	partial = strings.TrimLeft(partial, " \t\n\r")
+	if partial == "" {
+		return true
+	}
+	switch c := partial[0]; {
+	case c == '"' || c == '\'':
+		return bytes.IndexByte(added, c) >= 0
+	case isWordByte(c):
+		return slices.ContainsFunc(added, func(c byte) bool { return !isWordByte(c) })
+	}
+	return true
+}
+
+// isWordByte reports whether c may be part of a number, a literal or a JSON5
+// identifier.
+func isWordByte(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' ||
+		c == '-' || c == '+' || c == '.' || c == '_' || c == '$' || c == '\\' || c >= utf8.RuneSelf
+}
+
+func (s *Scanner) skipWhiteSpaces() {
+	currChar, size := utf8.DecodeRuneInString(s.text[s.pointer:])
+	for currChar == ' ' || currChar == '\n' || currChar == '\t' || currChar == '\r' {
+		s.pointer += size
+		currChar, size = utf8.DecodeRuneInString(s.text[s.pointer:])
+	}
+	if s.pointer == len(s.text) {
+		s.hitEnd = true
 	}
 }
 
 // check https://github.com/Ronit-Raj/json-parser/blob/main/README.md for automata
-func readNumber() (float64,error) {
+func (s *Scanner) readNumber() (float64, error) {
 	var state int8 = 0
-	var start int = pointer
+	var start int = s.pointer
 	var err SyntaxError
-	loop:
-	for pointer < len(Text) {
-		currentChar , charSize := utf8.DecodeRuneInString(Text[pointer:])
-		err.Msg = fmt.Sprintf("Unexpected chracter %c",currentChar)
-		switch state{
+	var currentChar rune
+loop:
+	for s.pointer < len(s.text) {
+		var charSize int
+		currentChar, charSize = utf8.DecodeRuneInString(s.text[s.pointer:])
+		switch state {
 		case 0:
-			if(currentChar=='0'){
+			if currentChar == '0' {
 				state = 1
-			}else if('1'<=currentChar && currentChar<='9'){
+			} else if '1' <= currentChar && currentChar <= '9' {
 				state = 3
-			}else if(currentChar=='-'){
+			} else if currentChar == '-' {
 				state = 2
-			}else{
+			} else {
 				state = -1
 			}
 		case 1:
-			if(currentChar=='.'){
-				state = 3
-			}else if(currentChar=='e' || currentChar=='E'){
+			if currentChar == '.' {
+				state = 4
+			} else if currentChar == 'e' || currentChar == 'E' {
 				state = 6
-			}else{
+			} else {
 				break loop
 			}
 		case 2:
-			if(currentChar=='0'){
+			if currentChar == '0' {
 				state = 1
-			}else if('1' <= currentChar && currentChar <='9'){
+			} else if '1' <= currentChar && currentChar <= '9' {
 				state = 3
-			}else{
+			} else {
 				state = -1
 			}
 		case 3:
-			if(unicode.IsDigit(currentChar)){
+			if isDigit(currentChar) {
 				state = 3
-			}else if(unicode.IsDigit(currentChar)){
-				state = 3
-			}else if(currentChar=='.'){
+			} else if currentChar == '.' {
 				state = 4
-			}else if(currentChar=='e' || currentChar=='E'){
+			} else if currentChar == 'e' || currentChar == 'E' {
 				state = 6
-			}else{
+			} else {
 				break loop
 			}
 		case 4:
-			if(unicode.IsDigit(currentChar)){
+			if isDigit(currentChar) {
 				state = 5
-			}else{
+			} else {
 				state = -1
 			}
 		case 5:
-			if(unicode.IsDigit(currentChar)){
+			if isDigit(currentChar) {
 				state = 5
-			}else if(currentChar=='e' || currentChar=='E'){
+			} else if currentChar == 'e' || currentChar == 'E' {
 				state = 6
-			}else {
+			} else {
 				break loop
 			}
 		case 6:
-			if(unicode.IsDigit(currentChar)){
+			if isDigit(currentChar) {
 				state = 8
-			}else if(currentChar=='-'){
+			} else if currentChar == '-' {
 				state = 7
-			}else if(currentChar=='+'){
+			} else if currentChar == '+' {
 				state = 7
-			}else{
+			} else {
 				state = -1
 			}
 		case 7:
-			if(unicode.IsDigit(currentChar)){
+			if isDigit(currentChar) {
 				state = 8
-			}else{
+			} else {
 				state = -1
 			}
 		case 8:
-			if(unicode.IsDigit(currentChar)){
+			if isDigit(currentChar) {
 				state = 8
-			}else if(currentChar=='.'){
-				state = 9
-			}else{
+			} else {
 				break loop
 			}
 		}
-		if(state==-1){
+		if state == -1 {
 			break
 		}
-		pointer+=charSize
+		s.pointer += charSize
+	}
+	if s.pointer == len(s.text) {
+		s.hitEnd = true
 	}
 
-	if(state!=1 && state!=3 && state!=5 && state!=8){
-		err.Position = pointer
-		return math.NaN(),err
-	}else{
-		num,_ := strconv.ParseFloat(Text[start:pointer],64)
-		return num , nil
+	if state != 1 && state != 3 && state != 5 && state != 8 {
+		if s.pointer == len(s.text) {
+			err = newSyntaxError(s.pointer, "unexpected end of input in number")
+		} else {
+			err = newSyntaxError(s.pointer, "invalid %s in number", describe(currentChar))
+		}
+		return math.NaN(), err
+	} else {
+		num, _ := strconv.ParseFloat(s.text[start:s.pointer], 64)
+		return num, nil
 	}
 }
 
-func readString() (string,error){
-	var stringVal string
-	startMarker := pointer 
-	peekPointer := pointer 
-	for peekPointer < len(Text) { //advancing peek pointer to find matching double quotes
-		peekChar, pSize := utf8.DecodeRuneInString(Text[peekPointer:])
-		if peekChar == '"' && Text[peekPointer-1] != 0x5C {
-			/*
-				this is the end of a string because we have found a closing double quotes and
-				no escape character
-			*/
-			stringVal = Text[startMarker:peekPointer]
-			peekPointer += pSize
-			pointer = peekPointer
-			return stringVal , nil
+// isDigit reports whether c is an ASCII digit, the only digits JSON has.
+func isDigit(c rune) bool {
+	return '0' <= c && c <= '9'
+}
+
+// readString reads a string whose opening quote has already been consumed
+// and returns its value with every escape sequence decoded. Bytes that are
+// not valid UTF-8 are replaced with U+FFFD.
+func (s *Scanner) readString(quote byte) (string, error) {
+	startMarker := s.pointer
+	var sb strings.Builder
+	escaped := false
+	chunk := startMarker // start of the characters not yet copied into sb
+	for peekPointer := startMarker; peekPointer < len(s.text); {
+		c := s.text[peekPointer]
+		switch {
+		case c == quote:
+			s.pointer = peekPointer + 1
+			if !escaped {
+				return s.text[startMarker:peekPointer], nil
+			}
+			sb.WriteString(s.text[chunk:peekPointer])
+			return sb.String(), nil
+		case c < 0x20 && (!s.json5 || c == '\n' || c == '\r'):
+			// JSON5 only rules out line breaks.
+			s.pointer = peekPointer
+			return "", newSyntaxError(peekPointer, "invalid control character %U in string", rune(c))
+		case c == '\\':
+			escaped = true
+			sb.WriteString(s.text[chunk:peekPointer])
+			r, size, err := s.readEscape(peekPointer)
+			if err != nil {
+				s.pointer = peekPointer
+				return "", err
+			}
+			if r != lineContinuation {
+				sb.WriteRune(r)
+			}
+			peekPointer += size
+			chunk = peekPointer
+		case c >= utf8.RuneSelf:
+			if !utf8.FullRuneInString(s.text[peekPointer:]) {
+				s.hitEnd = true
+			}
+			r, size := utf8.DecodeRuneInString(s.text[peekPointer:])
+			if r == utf8.RuneError && size == 1 {
+				// Invalid UTF-8 is replaced, so every decoded string is valid.
+				escaped = true
+				sb.WriteString(s.text[chunk:peekPointer])
+				sb.WriteRune(utf8.RuneError)
+				chunk = peekPointer + size
+			}
+			peekPointer += size
+		default:
+			peekPointer++
 		}
-		peekPointer += pSize
 	}
-	pointer = peekPointer
-	return "",SyntaxError{
-		Msg: "unterminated string",
-		Position: startMarker,
+	s.pointer = len(s.text)
+	s.hitEnd = true
+	return "", newSyntaxError(startMarker, "unterminated string")
+}
+
+// readEscape decodes the escape sequence starting with the backslash at
+// offset i. It returns the rune and the number of bytes the sequence spans.
+// A \u escape holding a UTF-16 high surrogate must be followed by one holding
+// the low surrogate, and the pair is joined into a single rune.
+func (s *Scanner) readEscape(i int) (rune, int, error) {
+	if i+1 >= len(s.text) {
+		s.hitEnd = true
+		return 0, 0, newSyntaxError(i, "unterminated string")
 	}
+	switch s.text[i+1] {
+	case '"':
+		return '"', 2, nil
+	case '\\':
+		return '\\', 2, nil
+	case '/':
+		return '/', 2, nil
+	case 'b':
+		return '\b', 2, nil
+	case 'f':
+		return '\f', 2, nil
+	case 'n':
+		return '\n', 2, nil
+	case 'r':
+		return '\r', 2, nil
+	case 't':
+		return '\t', 2, nil
+	case 'u':
+		r, ok := s.readHex4(i + 2)
+		if !ok {
+			return 0, 0, newSyntaxError(i, "invalid \\u escape, expected four hex digits")
+		}
+		if !utf16.IsSurrogate(r) {
+			return r, 6, nil
+		}
+		if r >= 0xDC00 {
+			return 0, 0, newSyntaxError(i, "unpaired low surrogate \\u%04X", r)
+		}
+		if i+7 >= len(s.text) {
+			s.hitEnd = true
+		} else if s.text[i+6] == '\\' && s.text[i+7] == 'u' {
+			if low, ok := s.readHex4(i + 8); ok {
+				if joined := utf16.DecodeRune(r, low); joined != utf8.RuneError {
+					return joined, 12, nil
+				}
+			}
+		}
+		return 0, 0, newSyntaxError(i, "unpaired high surrogate \\u%04X", r)
+	}
+	if s.json5 {
+		return s.readJSON5Escape(i)
+	}
+	r, _ := utf8.DecodeRuneInString(s.text[i+1:])
+	return 0, 0, newSyntaxError(i, "invalid escape sequence \\%c", r)
+}
+
+// readHex4 parses the four hex digits starting at offset i.
+func (s *Scanner) readHex4(i int) (rune, bool) {
+	if i+4 > len(s.text) {
+		s.hitEnd = true
+		return 0, false
+	}
+	var r rune
+	for _, c := range []byte(s.text[i : i+4]) {
+		switch {
+		case '0' <= c && c <= '9':
+			c -= '0'
+		case 'a' <= c && c <= 'f':
+			c = c - 'a' + 10
+		case 'A' <= c && c <= 'F':
+			c = c - 'A' + 10
+		default:
+			return 0, false
+		}
+		r = r<<4 | rune(c)
+	}
+	return r, true
 }
 
-func match(lex string) (bool){
-	for _,val := range lex {
-		if rune(Text[pointer]) != val {
+func (s *Scanner) match(lex string) bool {
+	for _, val := range lex {
+		if s.pointer >= len(s.text) {
+			s.hitEnd = true
+			return false
+		}
+		if rune(s.text[s.pointer]) != val {
 			return false
 		}
-		pointer++
+		s.pointer++
 	}
 	return true
 }
 
-func PeekToken() (Token,error){
-	peekPointer := pointer
-	peekToken,err := NextToken()
-	if(err!=nil){
-		pointer = peekPointer
-		return Token{0.0, "", EOF},err
+// readLiteral reads one of the literal names false, true and null.
+func (s *Scanner) readLiteral(lex string, typ TokenType) (Token, error) {
+	if s.match(lex) {
+		return Token{NumVal: math.NaN(), TypeOfToken: typ}, nil
+	}
+	if s.pointer >= len(s.text) {
+		return Token{}, newSyntaxError(s.pointer, "unexpected end of input in literal %s", lex)
 	}
-	pointer = peekPointer
-	return peekToken,nil
+	c, _ := utf8.DecodeRuneInString(s.text[s.pointer:])
+	return Token{}, newSyntaxError(s.pointer, "invalid %s in literal %s", describe(c), lex)
 }
 
-func NextToken() (Token, error) {
+// PeekToken returns the next token without consuming it.
+func (s *Scanner) PeekToken() (Token, error) {
+	peekOffset := s.Offset()
+	peekToken, err := s.NextToken()
+	// NextToken may have refilled the window, which moves base.
+	s.Rewind(peekOffset)
+	if err != nil {
+		return Token{TypeOfToken: EOF}, err
+	}
+	return peekToken, nil
+}
+
+// NextToken consumes and returns the next token. At the end of the input it
+// returns a token of type EOF.
+func (s *Scanner) NextToken() (Token, error) {
+	for {
+		start := s.pointer
+		s.hitEnd = false
+		currToken, err := s.scanToken()
+		if s.hitEnd && s.src != nil && s.srcErr == nil {
+			// The token may continue past the window, scan it again with more input.
+			s.pointer = start
+			s.fill()
+			continue
+		}
+		if s.hitEnd && s.srcErr != nil && s.srcErr != io.EOF {
+			return Token{TypeOfToken: EOF}, s.srcErr
+		}
+
+		currToken.Start += s.base
+		currToken.End += s.base
+		if syntaxErr, ok := err.(SyntaxError); ok {
+			syntaxErr.Location = s.Locate(syntaxErr.Position + s.base)
+			err = syntaxErr
+		}
+		return currToken, err
+	}
+}
+
+// scanToken scans one token from the window. Offsets in the token and in
+// errors are relative to the window.
+func (s *Scanner) scanToken() (Token, error) {
 	var currToken Token
 	var err error
-	if pointer < len(Text) {
-		currChar, size := utf8.DecodeRuneInString(Text[pointer:])
+	if s.json5 {
+		err = s.skipJSON5Space()
+	} else {
+		s.skipWhiteSpaces()
+	}
+	start := s.pointer
+	if err != nil {
+		return Token{TypeOfToken: EOF, Start: start, End: start}, err
+	}
+	if s.pointer < len(s.text) {
+		if !utf8.FullRuneInString(s.text[s.pointer:]) {
+			s.hitEnd = true
+		}
+		currChar, size := utf8.DecodeRuneInString(s.text[s.pointer:])
 
+		if s.json5 && startsJSON5Token(currChar) {
+			currToken, err = s.scanJSON5Token(currChar)
+			currToken.Raw = s.text[start:s.pointer]
+			currToken.Start, currToken.End = start, s.pointer
+			return currToken, err
+		}
 		switch currChar {
 		case rune(':'):
-			pointer += size
-			currToken = Token{math.NaN(), "", NAME_SEPARATOR}
+			s.pointer += size
+			currToken = Token{NumVal: math.NaN(), TypeOfToken: NAME_SEPARATOR}
 		case rune(','):
-			pointer += size
-			currToken = Token{math.NaN(), "", VALUE_SEPARATOR}
+			s.pointer += size
+			currToken = Token{NumVal: math.NaN(), TypeOfToken: VALUE_SEPARATOR}
 		case rune('{'):
-			pointer += size
-			currToken = Token{math.NaN(), "", BEGIN_OBJECT}
+			s.pointer += size
+			currToken = Token{NumVal: math.NaN(), TypeOfToken: BEGIN_OBJECT}
 		case rune('['):
-			pointer += size
-			currToken = Token{math.NaN(), "", BEGIN_ARRAY}
+			s.pointer += size
+			currToken = Token{NumVal: math.NaN(), TypeOfToken: BEGIN_ARRAY}
 		case rune(']'):
-			pointer += size
-			currToken = Token{math.NaN(), "", END_ARRAY}
+			s.pointer += size
+			currToken = Token{NumVal: math.NaN(), TypeOfToken: END_ARRAY}
 		case rune('}'):
-			pointer += size
-			currToken = Token{math.NaN(), "", END_OBJECT}
+			s.pointer += size
+			currToken = Token{NumVal: math.NaN(), TypeOfToken: END_OBJECT}
 		case rune('"'):
 			var stringVal string
-			pointer += size
-			stringVal , err = readString()
-			currToken = Token{math.NaN(),stringVal,STRING}
+			s.pointer += size
+			stringVal, err = s.readString('"')
+			currToken = Token{NumVal: math.NaN(), StringVal: stringVal, TypeOfToken: STRING}
 		case rune('f'):
-			if (match("false")){
-				currToken = Token{math.NaN(),"",LITERAL_FALSE}
-			}else {
-				errorMsg := fmt.Sprintf("Invalid Chracter:%c",currChar)
-				err = SyntaxError{errorMsg,pointer}
-			}
+			currToken, err = s.readLiteral("false", LITERAL_FALSE)
 		case rune('t'):
-			if (match("true")){
-				currToken = Token{math.NaN(),"",LITERAL_TRUE}
-			}else{
-				errorMsg := fmt.Sprintf("Invalid Chracter:%c",currChar)
-				err = SyntaxError{errorMsg,pointer}
-			}
+			currToken, err = s.readLiteral("true", LITERAL_TRUE)
 		case rune('n'):
-			if (match("null")){
-				currToken = Token{math.NaN(),"",LITERAL_NULL}
-			}else{
-				errorMsg := fmt.Sprintf("Invalid Chracter:%c",currChar)
-				err = SyntaxError{errorMsg,pointer}
-			}
-		case ' ', '\t', '\n', '\r':
-			skipWhiteSpaces()
-			currToken,err = NextToken()
+			currToken, err = s.readLiteral("null", LITERAL_NULL)
 		default:
-			if(unicode.IsNumber(currChar) || currChar=='-'){
+			if unicode.IsNumber(currChar) || currChar == '-' {
 				var numVal float64
-				numVal,err = readNumber()
-				currToken = Token{numVal,"",NUMBER}
-			}else{
-				errorMsg := fmt.Sprintf("Invalid Chracter:%c",currChar)
-				err = SyntaxError{errorMsg,pointer}
+				numVal, err = s.readNumber()
+				currToken = Token{NumVal: numVal, TypeOfToken: NUMBER}
+			} else {
+				err = newSyntaxError(s.pointer, "invalid %s looking for beginning of value", describe(currChar))
+				// Raw holds the character, so a parser can say what it found.
+				s.pointer += size
 			}
 		}
+		currToken.Raw = s.text[start:s.pointer]
+		currToken.Start, currToken.End = start, s.pointer
 		return currToken, err
 	}
-	return Token{0.0, "", EOF}, nil
+	return Token{TypeOfToken: EOF, Start: start, End: start}, nil
+}
+
+// Text is the input used by the package level NextToken and PeekToken.
+//
+// Deprecated: Text is shared by every caller in the process. Use New to get
+// a Scanner with its own input.
+var Text string
+var pointer int
+
+// ResetPointer moves the package level cursor back to the start of Text.
+//
+// Deprecated: use New.
+func ResetPointer() {
+	pointer = 0
+}
+
+// NextToken reads the next token from Text.
+//
+// Deprecated: use Scanner.NextToken.
+func NextToken() (Token, error) {
+	s := Scanner{text: Text, pointer: pointer}
+	token, err := s.NextToken()
+	pointer = s.pointer
+	return token, err
+}
+
+// PeekToken returns the next token from Text without consuming it.
+//
+// Deprecated: use Scanner.PeekToken.
+func PeekToken() (Token, error) {
+	s := Scanner{text: Text, pointer: pointer}
+	return s.PeekToken()
 }
@@ -1,7 +1,13 @@
 package scanner
 
 import (
+	"io"
+	"math"
+	"strings"
+	"sync"
 	"testing"
+	"testing/iotest"
+	"time"
 )
 
 func TestNextToken(t *testing.T) {
@@ -279,3 +285,372 @@ func TestNextToken(t *testing.T) {
 		})
 	}
 }
+
+func TestScannerIndependentInstances(t *testing.T) {
+	a := New(`[1, 2]`)
+	b := New(`{"key": true}`)
+
+	want := []struct {
+		s   *Scanner
+		typ TokenType
+	}{
+		{a, BEGIN_ARRAY},
+		{b, BEGIN_OBJECT},
+		{a, NUMBER},
+		{b, STRING},
+		{b, NAME_SEPARATOR},
+		{a, VALUE_SEPARATOR},
+		{b, LITERAL_TRUE},
+		{a, NUMBER},
+		{a, END_ARRAY},
+		{b, END_OBJECT},
+		{a, EOF},
+		{b, EOF},
+	}
+	for i, w := range want {
+		got, err := w.s.NextToken()
+		if err != nil {
+			t.Fatalf("token %d: unexpected error: %v", i, err)
+		}
+		if got.TypeOfToken != w.typ {
+			t.Errorf("token %d: expected token type %v, got %v", i, w.typ, got.TypeOfToken)
+		}
+	}
+}
+
+func TestScannerPeekToken(t *testing.T) {
+	s := New(` "peek" 1`)
+	peeked, err := s.PeekToken()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Offset() != 0 {
+		t.Errorf("PeekToken moved the cursor to %d", s.Offset())
+	}
+	next, err := s.NextToken()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if peeked.TypeOfToken != next.TypeOfToken || peeked.StringVal != next.StringVal {
+		t.Errorf("PeekToken returned %+v, NextToken returned %+v", peeked, next)
+	}
+}
+
+func TestScannerConcurrent(t *testing.T) {
+	inputs := []string{
+		`{"a": [1, 2, 3], "b": "text"}`,
+		`[true, false, null, -1.5e3]`,
+		`"just a string"`,
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		input := inputs[i%len(inputs)]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				s := New(input)
+				for {
+					tok, err := s.NextToken()
+					if err != nil {
+						t.Errorf("unexpected error on %q: %v", input, err)
+						return
+					}
+					if tok.TypeOfToken == EOF {
+						break
+					}
+				}
+				if s.Offset() != len(input) {
+					t.Errorf("scanner stopped at %d of %q", s.Offset(), input)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestReadStringEscapes(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+		errPos   int
+	}{
+		{name: "quote", input: `"say \"hi\""`, expected: `say "hi"`},
+		{name: "escaped backslash before quote", input: `"a\\"`, expected: `a\`},
+		{name: "solidus", input: `"a\/b"`, expected: "a/b"},
+		{name: "control escapes", input: `"\b\f\n\r\t"`, expected: "\b\f\n\r\t"},
+		{name: "newline between text", input: `"a\nb"`, expected: "a\nb"},
+		{name: "unicode escape", input: `"caf\u00e9"`, expected: "café"},
+		{name: "unicode escape uppercase", input: `"\u00C9"`, expected: "É"},
+		{name: "null character", input: `"\u0000"`, expected: "\x00"},
+		{name: "surrogate pair", input: `"\ud83d\ude00"`, expected: "😀"},
+		{name: "raw utf8 kept", input: `"😅 A"`, expected: "😅 A"},
+		{name: "invalid escape", input: `"ab\x"`, wantErr: true, errPos: 3},
+		{name: "short unicode escape", input: `"\u12"`, wantErr: true, errPos: 1},
+		{name: "bad hex digit", input: `"\u12G4"`, wantErr: true, errPos: 1},
+		{name: "lone high surrogate", input: `"x\ud83d"`, wantErr: true, errPos: 2},
+		{name: "high surrogate then letter", input: `"\ud83dA"`, wantErr: true, errPos: 1},
+		{name: "high surrogate then high surrogate", input: `"\ud83d\ud83d"`, wantErr: true, errPos: 1},
+		{name: "lone low surrogate", input: `"\ude00"`, wantErr: true, errPos: 1},
+		{name: "raw newline", input: "\"a\nb\"", wantErr: true, errPos: 2},
+		{name: "raw tab", input: "\"\t\"", wantErr: true, errPos: 1},
+		{name: "backslash at end", input: `"abc\`, wantErr: true, errPos: 4},
+		{name: "escaped quote is not the end", input: `"abc\"`, wantErr: true, errPos: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(tt.input).NextToken()
+			if tt.wantErr {
+				syntaxErr, ok := err.(SyntaxError)
+				if !ok {
+					t.Fatalf("expected SyntaxError, got %v", err)
+				}
+				if syntaxErr.Position != tt.errPos {
+					t.Errorf("expected error at %d, got %d (%v)", tt.errPos, syntaxErr.Position, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.TypeOfToken != STRING || got.StringVal != tt.expected {
+				t.Errorf("expected string %q, got %q", tt.expected, got.StringVal)
+			}
+		})
+	}
+}
+
+// repeatReader produces an array of n copies of item without ever holding the
+// whole document in memory.
+type repeatReader struct {
+	item      string
+	remaining int
+	pending   string
+	started   bool
+}
+
+func (r *repeatReader) Read(p []byte) (int, error) {
+	if r.pending == "" {
+		switch {
+		case !r.started:
+			r.pending, r.started = "[", true
+		case r.remaining > 1:
+			r.pending = r.item + ", "
+			r.remaining--
+		case r.remaining == 1:
+			r.pending = r.item + "]"
+			r.remaining--
+		default:
+			return 0, io.EOF
+		}
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+func TestNewReaderTokens(t *testing.T) {
+	input := `{"key": 123, "list": [1, -2.5e3], "s": "a\"b", "t": true, "f": false, "n": null}`
+	want, err := collectTokens(New(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	readers := map[string]io.Reader{
+		"one byte": iotest.OneByteReader(strings.NewReader(input)),
+		"half":     iotest.HalfReader(strings.NewReader(input)),
+	}
+	for name, r := range readers {
+		t.Run(name, func(t *testing.T) {
+			got, err := collectTokens(NewReader(r))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(want) {
+				t.Fatalf("expected %d tokens, got %d", len(want), len(got))
+			}
+			for i := range want {
+				if got[i].TypeOfToken != want[i].TypeOfToken || got[i].Raw != want[i].Raw ||
+					got[i].StringVal != want[i].StringVal ||
+					got[i].Start != want[i].Start || got[i].End != want[i].End {
+					t.Errorf("token %d: expected %+v, got %+v", i, want[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNewReaderErrorPosition(t *testing.T) {
+	input := strings.Repeat(" ", 10000) + `[1, @]`
+	s := NewReader(iotest.HalfReader(strings.NewReader(input)))
+	_, err := collectTokens(s)
+	syntaxErr, ok := err.(SyntaxError)
+	if !ok {
+		t.Fatalf("expected SyntaxError, got %v", err)
+	}
+	if syntaxErr.Position != 10004 {
+		t.Errorf("expected error at 10004, got %d", syntaxErr.Position)
+	}
+}
+
+func TestNewReaderBoundedWindow(t *testing.T) {
+	s := NewReader(&repeatReader{item: `{"id": 12345, "name": "record"}`, remaining: 100000})
+	tokens := 0
+	for {
+		tok, err := s.NextToken()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(s.text) > 2*minRead {
+			t.Fatalf("window grew to %d bytes", len(s.text))
+		}
+		if tok.TypeOfToken == EOF {
+			break
+		}
+		tokens++
+	}
+	if want := 2 + 100000*9 + 99999; tokens != want {
+		t.Errorf("expected %d tokens, got %d", want, tokens)
+	}
+}
+
+// chunkReader returns at most size bytes from each Read, like a network
+// connection does.
+type chunkReader struct {
+	r    io.Reader
+	size int
+}
+
+func (r chunkReader) Read(p []byte) (int, error) {
+	return r.r.Read(p[:min(len(p), r.size)])
+}
+
+func TestNewReaderLargeTokens(t *testing.T) {
+	// scan reads the single token of input in chunks and returns how long
+	// that took.
+	scan := func(input string) time.Duration {
+		begin := time.Now()
+		tok, err := NewReader(chunkReader{strings.NewReader(input), 1400}).NextToken()
+		elapsed := time.Since(begin)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if tok.Raw != input {
+			t.Fatalf("token of %d bytes, want %d", len(tok.Raw), len(input))
+		}
+		return elapsed
+	}
+	for _, large := range []func(n int) string{
+		func(n int) string { return `"` + strings.Repeat("x", n) + `"` },
+		func(n int) string { return strings.Repeat("1", n) },
+	} {
+		// Reading a token eight times as large takes about eight times as
+		// long, not sixty-four.
+		small, big := time.Duration(math.MaxInt64), time.Duration(math.MaxInt64)
+		for i := 0; i < 3; i++ {
+			small = min(small, scan(large(1<<19)))
+			big = min(big, scan(large(1<<22)))
+		}
+		if big > 24*small+10*time.Millisecond {
+			t.Errorf("a 4 MiB token took %v, a 512 KiB one %v", big, small)
+		}
+	}
+}
+
+func TestScannerHold(t *testing.T) {
+	input := `[1, {"a": "` + strings.Repeat("x", 3*minRead) + `"}, true]`
+	s := NewReader(iotest.OneByteReader(strings.NewReader(input)))
+	s.NextToken() // [
+	s.NextToken() // 1
+	s.NextToken() // ,
+	begin, _ := s.NextToken()
+	s.Hold(begin.Start)
+	for i := 0; i < 4; i++ {
+		s.NextToken() // "a" : "xxx..." }
+	}
+	end := s.Offset()
+	if got, want := s.Slice(begin.Start, end), input[begin.Start:end]; got != want {
+		t.Errorf("Slice() = %.20q..., want %.20q...", got, want)
+	}
+	s.Release()
+
+	// Once released the window shrinks again.
+	for {
+		tok, err := s.NextToken()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if tok.TypeOfToken == EOF {
+			break
+		}
+	}
+	if len(s.text) > 2*minRead {
+		t.Errorf("window kept %d bytes after Release", len(s.text))
+	}
+}
+
+func collectTokens(s *Scanner) ([]Token, error) {
+	var tokens []Token
+	for {
+		tok, err := s.NextToken()
+		if err != nil {
+			return tokens, err
+		}
+		tokens = append(tokens, tok)
+		if tok.TypeOfToken == EOF {
+			return tokens, nil
+		}
+	}
+}
+
+// FuzzNextToken checks that the scanner never panics, always makes progress
+// and reports errors inside the input.
+func FuzzNextToken(f *testing.F) {
+	for _, seed := range []string{`{"a": [1, -2.5e3, true, null]}`, `"é😀"`, `12.`, `tru`, "\"\\", "\xf0\x9f"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		for _, s := range []*Scanner{New(input), NewReader(iotest.OneByteReader(strings.NewReader(input)))} {
+			for last := -1; ; {
+				tok, err := s.NextToken()
+				if err != nil {
+					syntaxErr, ok := err.(SyntaxError)
+					if !ok || syntaxErr.Position < 0 || syntaxErr.Position > len(input) {
+						t.Fatalf("bad error %v for %q", err, input)
+					}
+					break
+				}
+				if tok.TypeOfToken == EOF {
+					break
+				}
+				if tok.End <= last || tok.Raw != input[tok.Start:tok.End] {
+					t.Fatalf("token %+v does not advance or match the input %q", tok, input)
+				}
+				last = tok.End
+			}
+		}
+	})
+}
+
+func TestReadNumberStrict(t *testing.T) {
+	valid := []string{`0`, `-0`, `0.5`, `-0.5e10`, `10E-2`, `1e+5`, `123.456`}
+	for _, input := range valid {
+		tok, err := New(input).NextToken()
+		if err != nil || tok.TypeOfToken != NUMBER || tok.Raw != input {
+			t.Errorf("NextToken(%q) = %+v, %v", input, tok, err)
+		}
+	}
+
+	// Each of these is either an error or a number followed by more input.
+	invalid := []string{`0.`, `0.1.2`, `1.5.3`, `1e5.3`, `-`, `1.e3`, `١٢`, `1٢`, `2.٣`}
+	for _, input := range invalid {
+		tok, err := New(input).NextToken()
+		if err == nil && tok.Raw == input {
+			t.Errorf("NextToken(%q) accepted the whole input as a number", input)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package schema
+
+import "fmt"
+
+// SchemaError reports a schema that cannot be compiled, such as one with a
+// keyword of the wrong type or a $ref that names nothing.
+type SchemaError struct {
+	Location string // a JSON pointer to the problem in the schema
+	Msg      string
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("invalid schema at %q: %s", e.Location, e.Msg)
+}
+
+// Violation is one way in which an instance fails to satisfy a schema.
+type Violation struct {
+	// InstanceLocation is a JSON pointer to the value that is wrong, such as
+	// "/items/2/price", or "" for the whole instance.
+	InstanceLocation string
+	// SchemaLocation is a JSON pointer to the keyword it fails in the schema
+	// document, such as "/$defs/item/properties/price/minimum". A keyword
+	// reached through $ref is located where it is written.
+	SchemaLocation string
+	Message        string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%q: %s (schema %q)", v.InstanceLocation, v.Message, v.SchemaLocation)
+}
+
+// ValidationError reports an instance that does not satisfy a schema, with
+// every violation found.
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	first := e.Violations[0].String()
+	if len(e.Violations) == 1 {
+		return first
+	}
+	return fmt.Sprintf("%s, and %d more violations", first, len(e.Violations)-1)
+}
+
+// schemaError returns a *SchemaError for the schema at loc.
+func schemaError(loc string, format string, args ...any) error {
+	return &SchemaError{Location: loc, Msg: fmt.Sprintf(format, args...)}
+}
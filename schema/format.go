@@ -0,0 +1,213 @@
+package schema
+
+import (
+	"net/netip"
+	"strings"
+	"time"
+)
+
+// formats holds the formats that are checked, by name.
+var formats = map[string]func(string) bool{
+	"date-time": isDateTime,
+	"email":     isEmail,
+	"uuid":      isUUID,
+	"ipv4":      isIPv4,
+	"ipv6":      isIPv6,
+}
+
+// isDateTime reports whether s is a date-time of RFC 3339, such as
+// 2024-02-29T12:30:00.5+01:00. A leap second must fall at 23:59:60 UTC.
+func isDateTime(s string) bool {
+	if len(s) < 20 || (s[10] != 'T' && s[10] != 't') {
+		return false
+	}
+	return isFullDate(s[:10]) && isFullTime(s[11:])
+}
+
+// isFullDate reports whether s is a date such as 2024-02-29.
+func isFullDate(s string) bool {
+	if len(s) != 10 || s[4] != '-' || s[7] != '-' {
+		return false
+	}
+	year, ok1 := decimal(s[:4])
+	month, ok2 := decimal(s[5:7])
+	day, ok3 := decimal(s[8:])
+	if !ok1 || !ok2 || !ok3 || month < 1 || month > 12 || day < 1 {
+		return false
+	}
+	// Day 0 of the next month is the last day of this one.
+	return day <= time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// isFullTime reports whether s is a time with an offset, such as
+// 12:30:00.5+01:00 or 23:59:60Z.
+func isFullTime(s string) bool {
+	if len(s) < 9 || s[2] != ':' || s[5] != ':' {
+		return false
+	}
+	hour, ok1 := decimal(s[:2])
+	minute, ok2 := decimal(s[3:5])
+	second, ok3 := decimal(s[6:8])
+	if !ok1 || !ok2 || !ok3 || hour > 23 || minute > 59 || second > 60 {
+		return false
+	}
+
+	rest := s[8:]
+	if strings.HasPrefix(rest, ".") {
+		digits := 1
+		for digits < len(rest) && '0' <= rest[digits] && rest[digits] <= '9' {
+			digits++
+		}
+		if digits == 1 {
+			return false
+		}
+		rest = rest[digits:]
+	}
+
+	offset := 0
+	switch {
+	case rest == "Z" || rest == "z":
+	case len(rest) == 6 && (rest[0] == '+' || rest[0] == '-') && rest[3] == ':':
+		h, ok1 := decimal(rest[1:3])
+		m, ok2 := decimal(rest[4:])
+		if !ok1 || !ok2 || h > 23 || m > 59 {
+			return false
+		}
+		offset = h*60 + m
+		if rest[0] == '-' {
+			offset = -offset
+		}
+	default:
+		return false
+	}
+
+	if second == 60 {
+		const minutesPerDay = 24 * 60
+		utc := ((hour*60+minute-offset)%minutesPerDay + minutesPerDay) % minutesPerDay
+		return utc == 23*60+59
+	}
+	return true
+}
+
+// decimal returns the value of s, which must be ASCII digits only.
+func decimal(s string) (int, bool) {
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, false
+		}
+		n = n*10 + int(s[i]-'0')
+	}
+	return n, s != ""
+}
+
+// isEmail reports whether s is an email address of RFC 5321, such as
+// joe.bloggs@example.com. The local part may be quoted, and the domain may
+// be an address literal such as [192.0.2.1] or [IPv6:2001:db8::1].
+func isEmail(s string) bool {
+	at := strings.LastIndexByte(s, '@')
+	if at < 0 {
+		return false
+	}
+	local, domain := s[:at], s[at+1:]
+
+	if len(local) >= 2 && local[0] == '"' && local[len(local)-1] == '"' {
+		if !isQuotedLocalPart(local[1 : len(local)-1]) {
+			return false
+		}
+	} else {
+		for atom := range strings.SplitSeq(local, ".") {
+			if atom == "" || strings.IndexFunc(atom, func(r rune) bool { return !isAtext(r) }) >= 0 {
+				return false
+			}
+		}
+	}
+
+	if literal, ok := strings.CutPrefix(domain, "["); ok {
+		literal, ok = strings.CutSuffix(literal, "]")
+		if v6, isV6 := strings.CutPrefix(literal, "IPv6:"); isV6 {
+			return ok && isIPv6(v6)
+		}
+		return ok && isIPv4(literal)
+	}
+	return isHostname(domain)
+}
+
+// isAtext reports whether c may appear unquoted in the local part of an
+// email address.
+func isAtext(c rune) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+		strings.ContainsRune("!#$%&'*+-/=?^_`{|}~", c)
+}
+
+// isQuotedLocalPart reports whether s may appear between the quotes of a
+// quoted local part: printable ASCII, with '"' and '\' escaped.
+func isQuotedLocalPart(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\':
+			i++
+			if i == len(s) || s[i] < ' ' || s[i] > '~' {
+				return false
+			}
+		case c == '"', c < ' ', c > '~':
+			return false
+		}
+	}
+	return true
+}
+
+// isHostname reports whether s is a domain name of dot-separated labels of
+// letters, digits and hyphens, where no label starts or ends with a hyphen.
+func isHostname(s string) bool {
+	if s == "" || len(s) > 253 {
+		return false
+	}
+	for label := range strings.SplitSeq(s, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, c := range label {
+			if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isUUID reports whether s is a UUID of RFC 9562 in its string form, such as
+// f81d4fae-7dec-11d0-a765-00a0c91e6bf6.
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return false
+			}
+		default:
+			if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isIPv4 reports whether s is an IPv4 address in dotted-quad form, without
+// leading zeros.
+func isIPv4(s string) bool {
+	addr, err := netip.ParseAddr(s)
+	return err == nil && addr.Is4()
+}
+
+// isIPv6 reports whether s is an IPv6 address of RFC 4291, without a zone.
+func isIPv6(s string) bool {
+	addr, err := netip.ParseAddr(s)
+	return err == nil && addr.Is6() && addr.Zone() == ""
+}
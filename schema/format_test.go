@@ -0,0 +1,44 @@
+package schema
+
+import "testing"
+
+func TestFormats(t *testing.T) {
+	tests := []struct {
+		format string
+		value  string
+		valid  bool
+	}{
+		{format: "date-time", value: "2024-12-31T23:59:59.999999999+14:00", valid: true},
+		{format: "date-time", value: "2024-01-01T00:59:60+01:00", valid: true},
+		{format: "date-time", value: "2024-01-01T00:00:00+24:00", valid: false},
+		{format: "date-time", value: "2024-13-01T00:00:00Z", valid: false},
+		{format: "date-time", value: "2024-00-01T00:00:00Z", valid: false},
+		{format: "date-time", value: "1900-02-29T00:00:00Z", valid: false},
+		{format: "date-time", value: "2000-02-29T00:00:00Z", valid: true},
+		{format: "date-time", value: "2024-01-01T00:00:00+0100", valid: false},
+		{format: "date-time", value: "2024-1-01T00:00:00Z", valid: false},
+		{format: "email", value: `"a\"b"@example.com`, valid: true},
+		{format: "email", value: `"a"b"@example.com`, valid: false},
+		{format: "email", value: "user@localhost", valid: true},
+		{format: "email", value: "us er@example.com", valid: false},
+		{format: "email", value: "josé@example.com", valid: false},
+		{format: "email", value: "a@b@example.com", valid: false},
+		{format: "email", value: "a@[IPv6:1::2::3]", valid: false},
+		{format: "email", value: "a@[127.0.0.1", valid: false},
+		{format: "uuid", value: "2eb8aa08-aa98-11ea-b4aa-73b441d16380", valid: true},
+		{format: "uuid", value: "{2eb8aa08-aa98-11ea-b4aa-73b441d16380}", valid: false},
+		{format: "ipv4", value: "0.0.0.0", valid: true},
+		{format: "ipv4", value: "1.2.3", valid: false},
+		{format: "ipv4", value: " 1.2.3.4", valid: false},
+		{format: "ipv6", value: "::", valid: true},
+		{format: "ipv6", value: "::FFFF:abcd", valid: true},
+		{format: "ipv6", value: "1:2:3:4:5:6:7", valid: false},
+		{format: "ipv6", value: "[::1]", valid: false},
+	}
+
+	for _, tt := range tests {
+		if got := formats[tt.format](tt.value); got != tt.valid {
+			t.Errorf("%s %q: valid = %v, want %v", tt.format, tt.value, got, tt.valid)
+		}
+	}
+}
@@ -0,0 +1,431 @@
+// Package schema validates JSON documents against a JSON Schema (draft
+// 2020-12). It supports the keywords most schemas for payloads are made of:
+//
+//   - type, enum and const,
+//   - properties, required and additionalProperties for objects,
+//   - prefixItems and items for arrays,
+//   - minimum, maximum, exclusiveMinimum and exclusiveMaximum for numbers,
+//     and minLength, maxLength, minItems, maxItems, minProperties and
+//     maxProperties,
+//   - pattern, and format for date-time, email, uuid, ipv4 and ipv6,
+//   - allOf, anyOf, oneOf and not,
+//   - $ref to a JSON pointer within the same schema, such as "#/$defs/item".
+//
+// Other keywords, such as title and description, are ignored, and so are
+// other formats. Patterns use the syntax of package regexp, which agrees
+// with ECMA-262 for the patterns schemas commonly use.
+package schema
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"math/big"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Ronit-Raj/json-parser/parser"
+	"github.com/Ronit-Raj/json-parser/pointer"
+)
+
+// Schema is a compiled schema. It is safe for concurrent use.
+type Schema struct {
+	root *node
+}
+
+// node is a compiled schema or subschema. Keywords that are absent have
+// their zero value, except for the lengths and counts, which are -1.
+type node struct {
+	loc   string // a JSON pointer to the schema in its document
+	never bool   // the schema is false
+
+	ref *node
+
+	types    []string
+	enum     []any
+	hasEnum  bool
+	constant any
+	hasConst bool
+
+	properties map[string]*node
+	required   []string
+	additional *node
+
+	prefixItems []*node
+	items       *node
+
+	minimum, maximum                   *bound
+	exclusiveMinimum, exclusiveMaximum *bound
+
+	minLength, maxLength         int
+	minItems, maxItems           int
+	minProperties, maxProperties int
+
+	pattern    *regexp.Regexp
+	format     func(string) bool
+	formatName string
+
+	allOf, anyOf, oneOf []*node
+	not                 *node
+}
+
+// bound is a number a keyword such as minimum holds.
+type bound struct {
+	value *big.Float
+	text  string // as written in the schema, for messages
+}
+
+// at returns the location of a keyword of n.
+func (n *node) at(keyword string) string {
+	return n.loc + "/" + keyword
+}
+
+// Compile compiles a schema, a document as parser.Decode builds it into an
+// interface value. The schema is an object or a boolean. A schema that is
+// malformed, or uses a $ref that is not a JSON pointer within it, gives a
+// *SchemaError.
+func Compile(doc any) (*Schema, error) {
+	c := &compiler{doc: doc, nodes: make(map[string]*node)}
+	root, err := c.compile(doc, "")
+	if err != nil {
+		return nil, err
+	}
+	// Resolving a reference may compile a schema with references of its own.
+	for i := 0; i < len(c.refs); i++ {
+		if err := c.resolve(c.refs[i]); err != nil {
+			return nil, err
+		}
+	}
+	return &Schema{root: root}, nil
+}
+
+// CompileText decodes the schema text and compiles it. See Compile.
+func CompileText(text string) (*Schema, error) {
+	var doc any
+	if err := parser.DecodeWithOptions(text, &doc, parser.UseNumber()); err != nil {
+		return nil, err
+	}
+	return Compile(doc)
+}
+
+// MustCompile is Compile for schemas known to be valid. It panics if doc is
+// not.
+func MustCompile(doc any) *Schema {
+	s, err := Compile(doc)
+	if err != nil {
+		panic("schema: Compile: " + err.Error())
+	}
+	return s
+}
+
+// compiler holds the state of a call to Compile.
+type compiler struct {
+	doc   any
+	nodes map[string]*node // every schema compiled so far, by location
+	refs  []reference      // the references to resolve
+}
+
+// reference is a $ref yet to be resolved.
+type reference struct {
+	from *node
+	ref  string
+}
+
+// compile compiles the schema v found at loc.
+func (c *compiler) compile(v any, loc string) (*node, error) {
+	if n, ok := c.nodes[loc]; ok {
+		return n, nil
+	}
+	n := &node{loc: loc, minLength: -1, maxLength: -1, minItems: -1, maxItems: -1, minProperties: -1, maxProperties: -1}
+	c.nodes[loc] = n
+	if b, ok := v.(bool); ok {
+		n.never = !b
+		return n, nil
+	}
+	keywords, ok := members(v)
+	if !ok {
+		return nil, schemaError(loc, "a schema must be an object or a boolean, not %s", kind(v))
+	}
+
+	for _, kw := range keywords {
+		if err := c.keyword(n, kw.Key, kw.Value); err != nil {
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+// keyword compiles the keyword name with the value v into n.
+func (c *compiler) keyword(n *node, name string, v any) error {
+	loc := n.at(pointer.Escape(name))
+	var err error
+	switch name {
+	case "$ref":
+		ref, ok := v.(string)
+		if !ok {
+			return schemaError(loc, "$ref must be a string")
+		}
+		// The reference is resolved once every schema is compiled.
+		c.refs = append(c.refs, reference{from: n, ref: ref})
+	case "$defs":
+		defs, ok := members(v)
+		if !ok {
+			return schemaError(loc, "$defs must be an object")
+		}
+		for _, def := range defs {
+			if _, err := c.compile(def.Value, loc+"/"+pointer.Escape(def.Key)); err != nil {
+				return err
+			}
+		}
+
+	case "type":
+		n.types, err = typeNames(v, loc)
+	case "enum":
+		values, ok := v.([]any)
+		if !ok {
+			return schemaError(loc, "enum must be an array")
+		}
+		n.enum, n.hasEnum = values, true
+	case "const":
+		n.constant, n.hasConst = v, true
+
+	case "properties":
+		props, ok := members(v)
+		if !ok {
+			return schemaError(loc, "properties must be an object")
+		}
+		n.properties = make(map[string]*node, len(props))
+		for _, prop := range props {
+			if n.properties[prop.Key], err = c.compile(prop.Value, loc+"/"+pointer.Escape(prop.Key)); err != nil {
+				return err
+			}
+		}
+	case "required":
+		n.required, err = stringList(v, loc)
+	case "additionalProperties":
+		n.additional, err = c.compile(v, loc)
+
+	case "prefixItems":
+		n.prefixItems, err = c.compileList(v, loc)
+	case "items":
+		if _, ok := v.([]any); ok {
+			return schemaError(loc, "items must be a schema; tuples use prefixItems since draft 2020-12")
+		}
+		n.items, err = c.compile(v, loc)
+
+	case "minimum":
+		n.minimum, err = numberBound(v, loc)
+	case "maximum":
+		n.maximum, err = numberBound(v, loc)
+	case "exclusiveMinimum":
+		n.exclusiveMinimum, err = numberBound(v, loc)
+	case "exclusiveMaximum":
+		n.exclusiveMaximum, err = numberBound(v, loc)
+	case "minLength":
+		n.minLength, err = count(v, loc)
+	case "maxLength":
+		n.maxLength, err = count(v, loc)
+	case "minItems":
+		n.minItems, err = count(v, loc)
+	case "maxItems":
+		n.maxItems, err = count(v, loc)
+	case "minProperties":
+		n.minProperties, err = count(v, loc)
+	case "maxProperties":
+		n.maxProperties, err = count(v, loc)
+
+	case "pattern":
+		p, ok := v.(string)
+		if !ok {
+			return schemaError(loc, "pattern must be a string")
+		}
+		if n.pattern, err = regexp.Compile(p); err != nil {
+			return schemaError(loc, "invalid pattern %q: %v", p, err)
+		}
+	case "format":
+		name, ok := v.(string)
+		if !ok {
+			return schemaError(loc, "format must be a string")
+		}
+		// Unknown formats are only annotations.
+		n.format, n.formatName = formats[name], name
+
+	case "allOf":
+		n.allOf, err = c.compileList(v, loc)
+	case "anyOf":
+		n.anyOf, err = c.compileList(v, loc)
+	case "oneOf":
+		n.oneOf, err = c.compileList(v, loc)
+	case "not":
+		n.not, err = c.compile(v, loc)
+	}
+	return err
+}
+
+// compileList compiles v, which must be a non-empty array of schemas.
+func (c *compiler) compileList(v any, loc string) ([]*node, error) {
+	list, ok := v.([]any)
+	if !ok || len(list) == 0 {
+		return nil, schemaError(loc, "%s must be a non-empty array of schemas", keywordOf(loc))
+	}
+	nodes := make([]*node, len(list))
+	for i, item := range list {
+		var err error
+		if nodes[i], err = c.compile(item, loc+"/"+strconv.Itoa(i)); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// resolve points r.from at the schema r.ref names, compiling that schema if
+// it is not a subschema compiled already.
+func (c *compiler) resolve(r reference) error {
+	ref, loc := r.ref, r.from.at("$ref")
+	fragment, ok := strings.CutPrefix(ref, "#")
+	if !ok {
+		return schemaError(loc, "$ref %q is not supported; only references within the schema, starting with #, are", ref)
+	}
+	fragment, err := url.PathUnescape(fragment)
+	if err != nil {
+		return schemaError(loc, "invalid $ref %q: %v", ref, err)
+	}
+	ptr, err := pointer.Parse(fragment)
+	if err != nil {
+		return schemaError(loc, "invalid $ref %q: %v", ref, err)
+	}
+	target, ok := c.nodes[ptr.String()]
+	if !ok {
+		v, err := ptr.Get(c.doc)
+		if err != nil {
+			return schemaError(loc, "$ref %q names nothing: %v", ref, err)
+		}
+		if target, err = c.compile(v, ptr.String()); err != nil {
+			return err
+		}
+	}
+	r.from.ref = target
+	return nil
+}
+
+// typeNames returns the value of the type keyword, a type name or an array
+// of them.
+func typeNames(v any, loc string) ([]string, error) {
+	names, err := stringList(v, loc)
+	if s, ok := v.(string); ok {
+		names, err = []string{s}, nil
+	}
+	if err != nil {
+		return nil, schemaError(loc, "type must be a string or an array of strings")
+	}
+	for _, name := range names {
+		switch name {
+		case "null", "boolean", "object", "array", "number", "integer", "string":
+		default:
+			return nil, schemaError(loc, "unknown type %q", name)
+		}
+	}
+	return names, nil
+}
+
+// stringList returns v, which must be an array of strings.
+func stringList(v any, loc string) ([]string, error) {
+	list, ok := v.([]any)
+	if !ok {
+		return nil, schemaError(loc, "%s must be an array of strings", keywordOf(loc))
+	}
+	strs := make([]string, len(list))
+	for i, item := range list {
+		if strs[i], ok = item.(string); !ok {
+			return nil, schemaError(loc, "%s must be an array of strings", keywordOf(loc))
+		}
+	}
+	return strs, nil
+}
+
+// numberBound returns the value of a keyword that must be a number.
+func numberBound(v any, loc string) (*bound, error) {
+	f, ok := number(v)
+	if !ok {
+		return nil, schemaError(loc, "%s must be a number", keywordOf(loc))
+	}
+	return &bound{value: f, text: describe(v)}, nil
+}
+
+// count returns the value of a keyword that must be a non-negative integer.
+// Counts too large for an int are as good as unlimited.
+func count(v any, loc string) (int, error) {
+	f, ok := number(v)
+	if !ok || !f.IsInt() || f.Sign() < 0 {
+		return 0, schemaError(loc, "%s must be a non-negative integer", keywordOf(loc))
+	}
+	// Int64 gives math.MaxInt64 for anything larger.
+	i, _ := f.Int64()
+	return int(min(i, math.MaxInt)), nil
+}
+
+// keywordOf returns the keyword a location in a schema ends with.
+func keywordOf(loc string) string {
+	return loc[strings.LastIndexByte(loc, '/')+1:]
+}
+
+// number returns the value of v if it is a JSON number, other than NaN.
+func number(v any) (*big.Float, bool) {
+	switch v := v.(type) {
+	case float64:
+		if math.IsNaN(v) {
+			return nil, false
+		}
+		return new(big.Float).SetFloat64(v), true
+	case parser.Number:
+		f, err := v.BigFloat()
+		return f, err == nil
+	}
+	return nil, false
+}
+
+// members returns the members of v and whether it is an object. Those of a
+// map are sorted by key, so that they always come in the same order.
+func members(v any) ([]parser.Member, bool) {
+	switch v := v.(type) {
+	case map[string]any:
+		result := make([]parser.Member, 0, len(v))
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			result = append(result, parser.Member{Key: key, Value: v[key]})
+		}
+		return result, true
+	case *parser.OrderedObject:
+		return v.Members(), true
+	}
+	return nil, false
+}
+
+// kind names the kind of JSON value v is for messages.
+func kind(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64, parser.Number:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any, *parser.OrderedObject:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// describe returns v as JSON text for messages.
+func describe(v any) string {
+	if text, err := parser.Encode(v); err == nil {
+		return text
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,122 @@
+package schema
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Ronit-Raj/json-parser/parser"
+)
+
+// caseGroup is a group of testdata/cases.json: a schema and instances it
+// accepts or rejects. The cases are written for this package and use the
+// fields of the JSON Schema Test Suite.
+type caseGroup struct {
+	Description string `json:"description"`
+	Schema      any    `json:"schema"`
+	Tests       []struct {
+		Description string `json:"description"`
+		Data        any    `json:"data"`
+		Valid       bool   `json:"valid"`
+	} `json:"tests"`
+}
+
+func TestCases(t *testing.T) {
+	data, err := os.ReadFile("testdata/cases.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Schemas and instances come as maps and float64s, or as ordered
+	// objects and Numbers.
+	decodings := map[string][]parser.Option{
+		"plain":   nil,
+		"ordered": {parser.UseOrderedObject(), parser.UseNumber()},
+	}
+	for name, opts := range decodings {
+		var groups []caseGroup
+		if err := parser.DecodeWithOptions(string(data), &groups, opts...); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		t.Run(name, func(t *testing.T) {
+			for _, group := range groups {
+				s, err := Compile(group.Schema)
+				if err != nil {
+					t.Errorf("%s: Compile() error = %v", group.Description, err)
+					continue
+				}
+				for _, tt := range group.Tests {
+					err := s.Validate(tt.Data)
+					if tt.Valid && err != nil {
+						t.Errorf("%s, %s: Validate() error = %v", group.Description, tt.Description, err)
+					}
+					if !tt.Valid && err == nil {
+						t.Errorf("%s, %s: Validate() succeeded, want an error", group.Description, tt.Description)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		schema   string
+		location string
+		message  string
+	}{
+		{schema: `1`, location: "", message: "a schema must be an object or a boolean, not number"},
+		{schema: `{"properties": {"a": "string"}}`, location: "/properties/a", message: "not string"},
+		{schema: `{"type": "float"}`, location: "/type", message: `unknown type "float"`},
+		{schema: `{"type": [1]}`, location: "/type", message: "type must be a string or an array of strings"},
+		{schema: `{"enum": 1}`, location: "/enum", message: "enum must be an array"},
+		{schema: `{"required": ["a", 1]}`, location: "/required", message: "required must be an array of strings"},
+		{schema: `{"items": [{}]}`, location: "/items", message: "tuples use prefixItems"},
+		{schema: `{"prefixItems": []}`, location: "/prefixItems", message: "prefixItems must be a non-empty array of schemas"},
+		{schema: `{"anyOf": {}}`, location: "/anyOf", message: "anyOf must be a non-empty array of schemas"},
+		{schema: `{"allOf": [{}, 2]}`, location: "/allOf/1", message: "a schema must be an object"},
+		{schema: `{"minimum": "1"}`, location: "/minimum", message: "minimum must be a number"},
+		{schema: `{"minLength": -1}`, location: "/minLength", message: "minLength must be a non-negative integer"},
+		{schema: `{"maxItems": 1.5}`, location: "/maxItems", message: "maxItems must be a non-negative integer"},
+		{schema: `{"pattern": "(a"}`, location: "/pattern", message: `invalid pattern "(a"`},
+		{schema: `{"format": true}`, location: "/format", message: "format must be a string"},
+		{schema: `{"$defs": {"a": {"not": 1}}}`, location: "/$defs/a/not", message: "a schema must be"},
+		{schema: `{"$ref": 1}`, location: "/$ref", message: "$ref must be a string"},
+		{schema: `{"$ref": "#/$defs/missing"}`, location: "/$ref", message: `$ref "#/$defs/missing" names nothing`},
+		{schema: `{"$ref": "other.json#/a"}`, location: "/$ref", message: "only references within the schema"},
+		{schema: `{"$ref": "#anchor"}`, location: "/$ref", message: `invalid $ref "#anchor"`},
+		{schema: `{"$ref": "#/$defs/bad", "$defs": {"bad": {"$ref": "#/nope"}}}`, location: "/$defs/bad/$ref", message: "names nothing"},
+		{schema: `{"$ref": "#/x", "x": {"type": 1}}`, location: "/x/type", message: "type must be"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.schema, func(t *testing.T) {
+			_, err := CompileText(tt.schema)
+			var schemaErr *SchemaError
+			if !errors.As(err, &schemaErr) {
+				t.Fatalf("CompileText() error = %v, want *SchemaError", err)
+			}
+			if schemaErr.Location != tt.location || !strings.Contains(schemaErr.Msg, tt.message) {
+				t.Errorf("CompileText() error at %q: %q, want %q at %q", schemaErr.Location, schemaErr.Msg, tt.message, tt.location)
+			}
+		})
+	}
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		if _, err := CompileText(`{"type": }`); err == nil || !strings.Contains(err.Error(), "1:10") {
+			t.Errorf("CompileText() error = %v, want a syntax error at 1:10", err)
+		}
+	})
+}
+
+func TestMustCompile(t *testing.T) {
+	if s := MustCompile(map[string]any{"type": "string"}); s.Validate("x") != nil {
+		t.Errorf("MustCompile() gave a schema that rejects a string")
+	}
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(string), "unknown type") {
+			t.Errorf("MustCompile() panic = %v, want the compile error", r)
+		}
+	}()
+	MustCompile(map[string]any{"type": "text"})
+}
@@ -0,0 +1,255 @@
+package schema
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Ronit-Raj/json-parser/patch"
+	"github.com/Ronit-Raj/json-parser/pointer"
+)
+
+// Validate checks instance, a document as parser.Decode builds it into an
+// interface value, against s. It returns nil if the instance is valid and a
+// *ValidationError listing every violation otherwise.
+//
+// Numbers are compared by value, so 1, 1.0 and parser.Number("1e0") are the
+// same, and parser.Number keeps every digit of the instance.
+func (s *Schema) Validate(instance any) error {
+	v := &validator{}
+	v.validate(s.root, instance)
+	if len(v.violations) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: v.violations}
+}
+
+// validator holds the state of a call to Validate.
+type validator struct {
+	path       []string // the reference tokens of the value being validated
+	violations []Violation
+	// active holds the $refs being followed, to catch a reference that leads
+	// back to itself without going deeper into the instance.
+	active map[activeRef]bool
+}
+
+// activeRef is a $ref followed at a depth in the instance. A $ref that is
+// active at the same depth is active for the same value, since the values
+// being validated only go deeper.
+type activeRef struct {
+	target *node
+	depth  int
+}
+
+// fail records a violation of the keyword at schemaLoc by the value being
+// validated.
+func (v *validator) fail(schemaLoc string, format string, args ...any) {
+	v.violations = append(v.violations, Violation{
+		InstanceLocation: pointer.New(v.path...).String(),
+		SchemaLocation:   schemaLoc,
+		Message:          fmt.Sprintf(format, args...),
+	})
+}
+
+// matches reports whether value satisfies n, without recording violations.
+func (v *validator) matches(n *node, value any) bool {
+	mark := len(v.violations)
+	v.validate(n, value)
+	ok := len(v.violations) == mark
+	v.violations = v.violations[:mark]
+	return ok
+}
+
+// descend validates value, found under token in the value being validated,
+// against n.
+func (v *validator) descend(token string, n *node, value any) {
+	v.path = append(v.path, token)
+	v.validate(n, value)
+	v.path = v.path[:len(v.path)-1]
+}
+
+func (v *validator) validate(n *node, value any) {
+	if n.never {
+		v.fail(n.loc, "no value is allowed here")
+		return
+	}
+	if n.ref != nil {
+		v.followRef(n, value)
+	}
+
+	if n.types != nil && !slices.ContainsFunc(n.types, func(t string) bool { return hasType(value, t) }) {
+		v.fail(n.at("type"), "expected %s, got %s", strings.Join(n.types, " or "), kind(value))
+	}
+	if n.hasEnum && !slices.ContainsFunc(n.enum, func(e any) bool { return patch.Equal(e, value) }) {
+		allowed := make([]string, len(n.enum))
+		for i, e := range n.enum {
+			allowed[i] = describe(e)
+		}
+		v.fail(n.at("enum"), "value must be one of %s", strings.Join(allowed, ", "))
+	}
+	if n.hasConst && !patch.Equal(n.constant, value) {
+		v.fail(n.at("const"), "value must be %s", describe(n.constant))
+	}
+
+	switch value := value.(type) {
+	case string:
+		v.string(n, value)
+	case []any:
+		v.array(n, value)
+	default:
+		if _, ok := number(value); ok {
+			v.number(n, value)
+		} else if _, ok := members(value); ok {
+			v.object(n, value)
+		}
+	}
+
+	for _, s := range n.allOf {
+		v.validate(s, value)
+	}
+	if n.anyOf != nil && !slices.ContainsFunc(n.anyOf, func(s *node) bool { return v.matches(s, value) }) {
+		v.fail(n.at("anyOf"), "value does not match any schema in anyOf")
+	}
+	if n.oneOf != nil {
+		var matched []int
+		for i, s := range n.oneOf {
+			if v.matches(s, value) {
+				matched = append(matched, i)
+			}
+		}
+		switch {
+		case len(matched) == 0:
+			v.fail(n.at("oneOf"), "value does not match any schema in oneOf")
+		case len(matched) > 1:
+			v.fail(n.at("oneOf"), "value matches schemas %d and %d in oneOf, but must match only one", matched[0], matched[1])
+		}
+	}
+	if n.not != nil && v.matches(n.not, value) {
+		v.fail(n.at("not"), "value must not match the schema in not")
+	}
+}
+
+// followRef validates value against the schema the $ref of n names.
+func (v *validator) followRef(n *node, value any) {
+	key := activeRef{target: n.ref, depth: len(v.path)}
+	if v.active[key] {
+		v.fail(n.at("$ref"), "$ref to %q loops back without going deeper into the value", n.ref.loc)
+		return
+	}
+	if v.active == nil {
+		v.active = make(map[activeRef]bool)
+	}
+	v.active[key] = true
+	v.validate(n.ref, value)
+	delete(v.active, key)
+}
+
+func (v *validator) number(n *node, value any) {
+	f, _ := number(value)
+	text := describe(value)
+	if n.minimum != nil && f.Cmp(n.minimum.value) < 0 {
+		v.fail(n.at("minimum"), "%s is less than the minimum of %s", text, n.minimum.text)
+	}
+	if n.exclusiveMinimum != nil && f.Cmp(n.exclusiveMinimum.value) <= 0 {
+		v.fail(n.at("exclusiveMinimum"), "%s is not greater than %s", text, n.exclusiveMinimum.text)
+	}
+	if n.maximum != nil && f.Cmp(n.maximum.value) > 0 {
+		v.fail(n.at("maximum"), "%s is greater than the maximum of %s", text, n.maximum.text)
+	}
+	if n.exclusiveMaximum != nil && f.Cmp(n.exclusiveMaximum.value) >= 0 {
+		v.fail(n.at("exclusiveMaximum"), "%s is not less than %s", text, n.exclusiveMaximum.text)
+	}
+}
+
+func (v *validator) string(n *node, s string) {
+	// Lengths count characters, not bytes.
+	length := utf8.RuneCountInString(s)
+	if n.minLength >= 0 && length < n.minLength {
+		v.fail(n.at("minLength"), "string has %d characters, fewer than the minimum of %d", length, n.minLength)
+	}
+	if n.maxLength >= 0 && length > n.maxLength {
+		v.fail(n.at("maxLength"), "string has %d characters, more than the maximum of %d", length, n.maxLength)
+	}
+	if n.pattern != nil && !n.pattern.MatchString(s) {
+		v.fail(n.at("pattern"), "string does not match the pattern %q", n.pattern)
+	}
+	if n.format != nil && !n.format(s) {
+		v.fail(n.at("format"), "string is not a valid %s", n.formatName)
+	}
+}
+
+func (v *validator) array(n *node, items []any) {
+	if n.minItems >= 0 && len(items) < n.minItems {
+		v.fail(n.at("minItems"), "array has %d items, fewer than the minimum of %d", len(items), n.minItems)
+	}
+	if n.maxItems >= 0 && len(items) > n.maxItems {
+		v.fail(n.at("maxItems"), "array has %d items, more than the maximum of %d", len(items), n.maxItems)
+	}
+
+	for i, item := range items {
+		s := n.items
+		if i < len(n.prefixItems) {
+			s = n.prefixItems[i]
+		}
+		if s == nil {
+			break
+		}
+		if s.never && s == n.items {
+			// One violation says more than one for each extra item.
+			v.fail(n.at("items"), "array has %d items, but only %d are allowed", len(items), len(n.prefixItems))
+			break
+		}
+		v.descend(fmt.Sprint(i), s, item)
+	}
+}
+
+func (v *validator) object(n *node, value any) {
+	props, _ := members(value)
+	if n.minProperties >= 0 && len(props) < n.minProperties {
+		v.fail(n.at("minProperties"), "object has %d properties, fewer than the minimum of %d", len(props), n.minProperties)
+	}
+	if n.maxProperties >= 0 && len(props) > n.maxProperties {
+		v.fail(n.at("maxProperties"), "object has %d properties, more than the maximum of %d", len(props), n.maxProperties)
+	}
+
+	present := make(map[string]bool, len(props))
+	for _, prop := range props {
+		present[prop.Key] = true
+	}
+	for _, name := range n.required {
+		if !present[name] {
+			v.fail(n.at("required"), "missing required property %q", name)
+		}
+	}
+
+	for _, prop := range props {
+		if s, ok := n.properties[prop.Key]; ok {
+			v.descend(prop.Key, s, prop.Value)
+			continue
+		}
+		switch {
+		case n.additional == nil:
+		case n.additional.never:
+			v.path = append(v.path, prop.Key)
+			v.fail(n.at("additionalProperties"), "property %q is not allowed", prop.Key)
+			v.path = v.path[:len(v.path)-1]
+		default:
+			v.descend(prop.Key, n.additional, prop.Value)
+		}
+	}
+}
+
+// hasType reports whether value is of the JSON Schema type t. An integer is
+// any number without a fractional part, such as 1.0.
+func hasType(value any, t string) bool {
+	switch t {
+	case "integer":
+		f, ok := number(value)
+		return ok && f.IsInt()
+	case "number":
+		_, ok := number(value)
+		return ok || kind(value) == "number"
+	}
+	return kind(value) == t
+}
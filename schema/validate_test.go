@@ -0,0 +1,197 @@
+package schema
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Ronit-Raj/json-parser/parser"
+)
+
+// orderSchema describes an order, with a reference for its items.
+const orderSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"properties": {
+		"id": {"type": "string", "format": "uuid"},
+		"email": {"type": "string", "format": "email"},
+		"placed": {"type": "string", "format": "date-time"},
+		"status": {"enum": ["new", "paid", "shipped"]},
+		"items": {"type": "array", "minItems": 1, "items": {"$ref": "#/$defs/item"}},
+		"note": {"type": "string", "maxLength": 5}
+	},
+	"required": ["id", "items"],
+	"additionalProperties": false,
+	"$defs": {
+		"item": {
+			"type": "object",
+			"properties": {
+				"sku": {"type": "string", "pattern": "^[A-Z]{3}-[0-9]+$"},
+				"quantity": {"type": "integer", "minimum": 1},
+				"price": {"type": "number", "exclusiveMinimum": 0}
+			},
+			"required": ["sku", "quantity"]
+		}
+	}
+}`
+
+func TestValidate(t *testing.T) {
+	s, err := CompileText(orderSchema)
+	if err != nil {
+		t.Fatalf("CompileText() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		instance string
+		expected []Violation
+	}{
+		{name: "valid", instance: `{"id": "2eb8aa08-aa98-11ea-b4aa-73b441d16380", "email": "a@example.com",
+			"placed": "2024-02-29T12:00:00Z", "status": "paid", "items": [{"sku": "ABC-1", "quantity": 2, "price": 9.5}]}`},
+		{name: "every violation", instance: `{"id": "x", "status": "lost", "items": [{"sku": "abc", "quantity": 0}, {"price": 0}], "extra": 1, "note": "héllo!"}`,
+			expected: []Violation{
+				{InstanceLocation: "/extra", SchemaLocation: "/additionalProperties", Message: `property "extra" is not allowed`},
+				{InstanceLocation: "/id", SchemaLocation: "/properties/id/format", Message: "string is not a valid uuid"},
+				{InstanceLocation: "/items/0/quantity", SchemaLocation: "/$defs/item/properties/quantity/minimum", Message: "0 is less than the minimum of 1"},
+				{InstanceLocation: "/items/0/sku", SchemaLocation: "/$defs/item/properties/sku/pattern", Message: `string does not match the pattern "^[A-Z]{3}-[0-9]+$"`},
+				{InstanceLocation: "/items/1", SchemaLocation: "/$defs/item/required", Message: `missing required property "sku"`},
+				{InstanceLocation: "/items/1", SchemaLocation: "/$defs/item/required", Message: `missing required property "quantity"`},
+				{InstanceLocation: "/items/1/price", SchemaLocation: "/$defs/item/properties/price/exclusiveMinimum", Message: "0 is not greater than 0"},
+				{InstanceLocation: "/note", SchemaLocation: "/properties/note/maxLength", Message: "string has 6 characters, more than the maximum of 5"},
+				{InstanceLocation: "/status", SchemaLocation: "/properties/status/enum", Message: `value must be one of "new", "paid", "shipped"`},
+			}},
+		{name: "wrong type", instance: `[]`,
+			expected: []Violation{{InstanceLocation: "", SchemaLocation: "/type", Message: "expected object, got array"}}},
+		{name: "missing and empty", instance: `{"items": []}`,
+			expected: []Violation{
+				{InstanceLocation: "", SchemaLocation: "/required", Message: `missing required property "id"`},
+				{InstanceLocation: "/items", SchemaLocation: "/properties/items/minItems", Message: "array has 0 items, fewer than the minimum of 1"},
+			}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var instance any
+			if err := parser.Decode(tt.instance, &instance); err != nil {
+				t.Fatal(err)
+			}
+			err := s.Validate(instance)
+			if tt.expected == nil {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Validate() error = %v, want *ValidationError", err)
+			}
+			if !reflect.DeepEqual(validationErr.Violations, tt.expected) {
+				t.Errorf("Validate() violations =\n%v\nwant\n%v", validationErr.Violations, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidationErrorMessage(t *testing.T) {
+	s := MustCompile(map[string]any{"items": map[string]any{"type": "string"}})
+	err := s.Validate([]any{"a", 1.0, true})
+	want := `"/1": expected string, got number (schema "/items/type"), and 1 more violations`
+	if err == nil || err.Error() != want {
+		t.Errorf("Error() = %v, want %q", err, want)
+	}
+	err = s.Validate([]any{nil})
+	if want := `"/0": expected string, got null (schema "/items/type")`; err == nil || err.Error() != want {
+		t.Errorf("Error() = %v, want %q", err, want)
+	}
+}
+
+func TestValidateCombinators(t *testing.T) {
+	tests := []struct {
+		schema   string
+		instance any
+		expected []Violation
+	}{
+		{schema: `{"anyOf": [{"type": "string"}, {"type": "integer"}]}`, instance: 1.5,
+			expected: []Violation{{SchemaLocation: "/anyOf", Message: "value does not match any schema in anyOf"}}},
+		{schema: `{"oneOf": [{"minimum": 0}, {"maximum": 10}]}`, instance: 5.0,
+			expected: []Violation{{SchemaLocation: "/oneOf", Message: "value matches schemas 0 and 1 in oneOf, but must match only one"}}},
+		{schema: `{"not": {"const": "admin"}}`, instance: "admin",
+			expected: []Violation{{SchemaLocation: "/not", Message: "value must not match the schema in not"}}},
+		{schema: `{"allOf": [{"minimum": 2}, {"multipleOf": 2}, {"maximum": 0}]}`, instance: 1.0,
+			expected: []Violation{{SchemaLocation: "/allOf/0/minimum", Message: "1 is less than the minimum of 2"},
+				{SchemaLocation: "/allOf/2/maximum", Message: "1 is greater than the maximum of 0"}}},
+		{schema: `{"const": {"a": [1]}}`, instance: map[string]any{},
+			expected: []Violation{{SchemaLocation: "/const", Message: `value must be {"a":[1]}`}}},
+		{schema: `{"prefixItems": [{"type": "integer"}], "items": false}`, instance: []any{1.0, 2.0, 3.0},
+			expected: []Violation{{SchemaLocation: "/items", Message: "array has 3 items, but only 1 are allowed"}}},
+		{schema: `{"properties": {"a": false}}`, instance: map[string]any{"a": 1.0},
+			expected: []Violation{{InstanceLocation: "/a", SchemaLocation: "/properties/a", Message: "no value is allowed here"}}},
+		{schema: `{"additionalProperties": {"maxProperties": 0}}`, instance: map[string]any{"a/b": map[string]any{"c": nil}},
+			expected: []Violation{{InstanceLocation: "/a~1b", SchemaLocation: "/additionalProperties/maxProperties", Message: "object has 1 properties, more than the maximum of 0"}}},
+		{schema: `{"$defs": {"a": {"$ref": "#/$defs/b"}, "b": {"$ref": "#/$defs/a"}}, "$ref": "#/$defs/a"}`, instance: 1.0,
+			expected: []Violation{{SchemaLocation: "/$defs/b/$ref", Message: `$ref to "/$defs/a" loops back without going deeper into the value`}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.schema, func(t *testing.T) {
+			s, err := CompileText(tt.schema)
+			if err != nil {
+				t.Fatalf("CompileText() error = %v", err)
+			}
+			var validationErr *ValidationError
+			if err := s.Validate(tt.instance); !errors.As(err, &validationErr) {
+				t.Fatalf("Validate() error = %v, want *ValidationError", err)
+			}
+			if !reflect.DeepEqual(validationErr.Violations, tt.expected) {
+				t.Errorf("Validate() violations =\n%v\nwant\n%v", validationErr.Violations, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidateNumbers(t *testing.T) {
+	tests := []struct {
+		schema   string
+		instance any
+		valid    bool
+	}{
+		{schema: `{"maximum": 9007199254740992}`, instance: parser.Number("9007199254740993"), valid: false},
+		{schema: `{"maximum": 9007199254740993}`, instance: parser.Number("9007199254740993"), valid: true},
+		{schema: `{"type": "integer"}`, instance: parser.Number("1e400"), valid: true},
+		{schema: `{"type": "integer"}`, instance: parser.Number("1.0000000000000000001"), valid: false},
+		{schema: `{"minimum": 0.1}`, instance: 0.1, valid: true},
+		{schema: `{"enum": [1]}`, instance: parser.Number("1.0"), valid: true},
+		{schema: `{"maxLength": 1e30}`, instance: "long enough", valid: true},
+	}
+
+	for _, tt := range tests {
+		s, err := CompileText(tt.schema)
+		if err != nil {
+			t.Fatalf("CompileText(%s) error = %v", tt.schema, err)
+		}
+		if err := s.Validate(tt.instance); (err == nil) != tt.valid {
+			t.Errorf("%s: Validate(%v) error = %v, want valid %v", tt.schema, tt.instance, err, tt.valid)
+		}
+	}
+}
+
+func TestValidateOrderedObject(t *testing.T) {
+	var instance any
+	if err := parser.DecodeWithOptions(`{"b": 1, "a": 2}`, &instance, parser.UseOrderedObject()); err != nil {
+		t.Fatal(err)
+	}
+	s := MustCompile(map[string]any{"additionalProperties": false})
+	var validationErr *ValidationError
+	if err := s.Validate(instance); !errors.As(err, &validationErr) || len(validationErr.Violations) != 2 {
+		t.Fatalf("Validate() error = %v, want two violations", err)
+	}
+	// The violations follow the order of the instance.
+	if got := validationErr.Violations[0].InstanceLocation; got != "/b" {
+		t.Errorf("first violation at %q, want /b", got)
+	}
+	if !strings.Contains(validationErr.Error(), "and 1 more") {
+		t.Errorf("Error() = %q", validationErr.Error())
+	}
+}